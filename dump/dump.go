@@ -0,0 +1,386 @@
+package dump
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/joyme123/thrift-ls/parser"
+)
+
+// Dump parses file and converts it to schema. If resolveIncludes is true, all included
+// files are parsed and dumped recursively, each file appears only once.
+func Dump(filename string, resolveIncludes bool) (*Schema, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{
+		Version: SchemaVersion,
+	}
+
+	dumped := make(map[string]struct{})
+	var dumpFile func(filename string, content []byte)
+	dumpFile = func(filename string, content []byte) {
+		dumped[filename] = struct{}{}
+
+		psr := &parser.PEGParser{}
+		doc, errs := psr.Parse(filename, content)
+		if doc == nil {
+			doc = &parser.Document{Filename: filename}
+		}
+		file := DumpDocument(doc)
+		file.Filename = filename
+		file.Errors = append(file.Errors, dumpErrors(errs)...)
+		schema.Files = append(schema.Files, file)
+
+		if !resolveIncludes {
+			return
+		}
+
+		for _, include := range file.Includes {
+			includeFile := filepath.Join(filepath.Dir(filename), include.Path)
+			includeContent, err := os.ReadFile(includeFile)
+			if err != nil {
+				file.Errors = append(file.Errors, &Error{
+					Message: fmt.Sprintf("failed to read include file %s: %v", include.Path, err),
+					Range:   include.Range,
+				})
+				continue
+			}
+			include.ResolvedPath = includeFile
+			if _, ok := dumped[includeFile]; ok {
+				continue
+			}
+			dumpFile(includeFile, includeContent)
+		}
+	}
+	dumpFile(filename, content)
+
+	return schema, nil
+}
+
+// DumpDocument converts document to schema file. Bad nodes are skipped
+func DumpDocument(doc *parser.Document) *File {
+	file := &File{
+		Filename:    doc.Filename,
+		Includes:    make([]*Include, 0),
+		CPPIncludes: make([]string, 0),
+		Namespaces:  make([]*Namespace, 0),
+		Definitions: make([]*Definition, 0),
+		Errors:      make([]*Error, 0),
+	}
+
+	for _, include := range doc.Includes {
+		if include.BadNode || include.Path == nil || include.Path.BadNode || include.Path.Value == nil {
+			continue
+		}
+		file.Includes = append(file.Includes, &Include{
+			Path:   include.Path.Value.Text,
+			Prefix: include.Name(),
+			Range:  dumpRange(include),
+		})
+	}
+
+	for _, include := range doc.CPPIncludes {
+		if include.BadNode || include.Path == nil || include.Path.BadNode || include.Path.Value == nil {
+			continue
+		}
+		file.CPPIncludes = append(file.CPPIncludes, include.Path.Value.Text)
+	}
+
+	for _, ns := range doc.Namespaces {
+		if ns.BadNode || ns.Language == nil || ns.Name == nil {
+			continue
+		}
+		file.Namespaces = append(file.Namespaces, &Namespace{
+			Scope:       identifierName(&ns.Language.Identifier),
+			Name:        identifierName(ns.Name),
+			Annotations: dumpAnnotations(ns.Annotations),
+			Range:       dumpRange(ns),
+		})
+	}
+
+	for _, node := range doc.Nodes {
+		if node.IsBadNode() {
+			continue
+		}
+		var def *Definition
+		switch node.Type() {
+		case "Struct":
+			st := node.(*parser.Struct)
+			def = &Definition{
+				Kind:        KindStruct,
+				Name:        identifierName(st.Identifier),
+				Doc:         st.Doc(),
+				Annotations: dumpAnnotations(st.Annotations),
+				Fields:      dumpFields(st.Fields),
+			}
+		case "Union":
+			union := node.(*parser.Union)
+			def = &Definition{
+				Kind:        KindUnion,
+				Name:        identifierName(union.Name),
+				Doc:         union.Doc(),
+				Annotations: dumpAnnotations(union.Annotations),
+				Fields:      dumpFields(union.Fields),
+			}
+		case "Exception":
+			exception := node.(*parser.Exception)
+			def = &Definition{
+				Kind:        KindException,
+				Name:        identifierName(exception.Name),
+				Doc:         exception.Doc(),
+				Annotations: dumpAnnotations(exception.Annotations),
+				Fields:      dumpFields(exception.Fields),
+			}
+		case "Enum":
+			enum := node.(*parser.Enum)
+			def = &Definition{
+				Kind:        KindEnum,
+				Name:        identifierName(enum.Name),
+				Doc:         enum.Doc(),
+				Annotations: dumpAnnotations(enum.Annotations),
+				Values:      dumpEnumValues(enum.Values),
+			}
+		case "Service":
+			svc := node.(*parser.Service)
+			def = &Definition{
+				Kind:        KindService,
+				Name:        identifierName(svc.Name),
+				Doc:         svc.Doc(),
+				Annotations: dumpAnnotations(svc.Annotations),
+				Extends:     identifierName(svc.Extends),
+				Functions:   dumpFunctions(svc.Functions),
+			}
+		case "Const":
+			cst := node.(*parser.Const)
+			def = &Definition{
+				Kind:        KindConst,
+				Name:        identifierName(cst.Name),
+				Doc:         cst.Doc(),
+				Annotations: dumpAnnotations(cst.Annotations),
+				Type:        dumpType(cst.ConstType),
+				Value:       dumpConstValue(cst.Value),
+			}
+		case "Typedef":
+			td := node.(*parser.Typedef)
+			def = &Definition{
+				Kind:        KindTypedef,
+				Name:        identifierName(td.Alias),
+				Doc:         td.Doc(),
+				Annotations: dumpAnnotations(td.Annotations),
+				Type:        dumpType(td.T),
+			}
+		default:
+			continue
+		}
+		def.Range = dumpRange(node)
+		file.Definitions = append(file.Definitions, def)
+	}
+
+	return file
+}
+
+func dumpFields(fields []*parser.Field) []*Field {
+	res := make([]*Field, 0, len(fields))
+	for _, field := range fields {
+		if field.BadNode {
+			continue
+		}
+		f := &Field{
+			Requiredness: RequirednessDefault,
+			Type:         dumpType(field.FieldType),
+			Name:         identifierName(field.Identifier),
+			Default:      dumpConstValue(field.ConstValue),
+			Doc:          field.Doc(),
+			Annotations:  dumpAnnotations(field.Annotations),
+			Range:        dumpRange(field),
+		}
+		if field.Index != nil && !field.Index.BadNode {
+			f.ID = field.Index.Value
+		}
+		if field.RequiredKeyword != nil && field.RequiredKeyword.Literal != nil {
+			f.Requiredness = field.RequiredKeyword.Literal.Text
+		}
+		res = append(res, f)
+	}
+
+	return res
+}
+
+func dumpEnumValues(values []*parser.EnumValue) []*EnumValue {
+	res := make([]*EnumValue, 0, len(values))
+	for _, v := range values {
+		if v.BadNode {
+			continue
+		}
+		res = append(res, &EnumValue{
+			Name:        identifierName(v.Name),
+			Value:       v.Value,
+			Explicit:    v.ValueNode != nil,
+			Doc:         v.Doc(),
+			Annotations: dumpAnnotations(v.Annotations),
+			Range:       dumpRange(v),
+		})
+	}
+
+	return res
+}
+
+func dumpFunctions(fns []*parser.Function) []*Function {
+	res := make([]*Function, 0, len(fns))
+	for _, fn := range fns {
+		if fn.BadNode {
+			continue
+		}
+		f := &Function{
+			Name:        identifierName(fn.Name),
+			Oneway:      fn.Oneway != nil,
+			Arguments:   dumpFields(fn.Arguments),
+			Doc:         fn.Doc(),
+			Annotations: dumpAnnotations(fn.Annotations),
+			Range:       dumpRange(fn),
+		}
+		if fn.Void != nil {
+			f.ReturnType = &Type{Name: "void"}
+		} else {
+			f.ReturnType = dumpType(fn.FunctionType)
+		}
+		if fn.Throws != nil {
+			f.Throws = dumpFields(fn.Throws.Fields)
+		}
+		res = append(res, f)
+	}
+
+	return res
+}
+
+func dumpType(ft *parser.FieldType) *Type {
+	if ft == nil || ft.BadNode || ft.TypeName == nil {
+		return nil
+	}
+
+	t := &Type{
+		Name:        ft.TypeName.Name,
+		Annotations: dumpAnnotations(ft.Annotations),
+	}
+	switch ft.TypeName.Name {
+	case "list", "set":
+		t.ElemType = dumpType(ft.KeyType)
+	case "map":
+		t.KeyType = dumpType(ft.KeyType)
+		t.ValueType = dumpType(ft.ValueType)
+	}
+
+	return t
+}
+
+func dumpConstValue(cv *parser.ConstValue) *ConstValue {
+	if cv == nil || cv.BadNode {
+		return nil
+	}
+
+	switch cv.TypeName {
+	case "i64":
+		return &ConstValue{Kind: ConstKindInt, Value: cv.Value, Text: cv.ValueInText}
+	case "double":
+		return &ConstValue{Kind: ConstKindDouble, Value: cv.Value, Text: cv.ValueInText}
+	case "string":
+		if literal, ok := cv.Value.(*parser.Literal); ok {
+			if literal.Value == nil {
+				return nil
+			}
+			return &ConstValue{Kind: ConstKindString, Value: literal.Value.Text}
+		}
+		return &ConstValue{Kind: ConstKindString, Value: cv.Value}
+	case "identifier":
+		return &ConstValue{Kind: ConstKindIdentifier, Value: cv.Value}
+	case "list":
+		res := &ConstValue{Kind: ConstKindList, Items: make([]*ConstValue, 0)}
+		values, _ := cv.Value.([]*parser.ConstValue)
+		for _, v := range values {
+			if item := dumpConstValue(v); item != nil {
+				res.Items = append(res.Items, item)
+			}
+		}
+		return res
+	case "map":
+		res := &ConstValue{Kind: ConstKindMap, Entries: make([]*ConstMapEntry, 0)}
+		values, _ := cv.Value.([]*parser.ConstValue)
+		for _, pair := range values {
+			if pair == nil || pair.BadNode || pair.TypeName != "pair" {
+				continue
+			}
+			key, _ := pair.Key.(*parser.ConstValue)
+			value, _ := pair.Value.(*parser.ConstValue)
+			res.Entries = append(res.Entries, &ConstMapEntry{
+				Key:   dumpConstValue(key),
+				Value: dumpConstValue(value),
+			})
+		}
+		return res
+	}
+
+	return nil
+}
+
+func dumpAnnotations(annos *parser.Annotations) []*Annotation {
+	if annos == nil || annos.BadNode {
+		return nil
+	}
+
+	res := make([]*Annotation, 0, len(annos.Annotations))
+	for _, anno := range annos.Annotations {
+		if anno.BadNode || anno.Value == nil || anno.Value.Value == nil {
+			continue
+		}
+		res = append(res, &Annotation{
+			Key:   identifierName(anno.Identifier),
+			Value: anno.Value.Value.Text,
+		})
+	}
+
+	return res
+}
+
+func dumpErrors(errs []error) []*Error {
+	res := make([]*Error, 0, len(errs))
+	for _, err := range errs {
+		e := &Error{Message: err.Error()}
+		if parserErr, ok := err.(parser.ParserError); ok {
+			if parserErr.InnerError() != nil {
+				e.Message = parserErr.InnerError().Error()
+			}
+			line, col, offset := parserErr.Pos()
+			pos := Position{Line: line, Col: col, Offset: offset}
+			e.Range = Range{Start: pos, End: pos}
+		}
+		res = append(res, e)
+	}
+
+	return res
+}
+
+func dumpRange(node parser.Node) Range {
+	return Range{
+		Start: Position{
+			Line:   node.Pos().Line,
+			Col:    node.Pos().Col,
+			Offset: node.Pos().Offset,
+		},
+		End: Position{
+			Line:   node.End().Line,
+			Col:    node.End().Col,
+			Offset: node.End().Offset,
+		},
+	}
+}
+
+func identifierName(id *parser.Identifier) string {
+	if id == nil || id.BadNode || id.Name == nil {
+		return ""
+	}
+	return id.Name.Text
+}
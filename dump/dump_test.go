@@ -0,0 +1,129 @@
+package dump
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joyme123/thrift-ls/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpDocument(t *testing.T) {
+	content := `include "base.thrift"
+namespace go user
+
+/**
+ * User info
+ */
+struct User {
+    1: required i64 id (api.path = "id")
+    2: optional list<string> tags = ["a"] // tags of user
+    3: map<string, base.Item> items
+}
+
+enum Status {
+    OK
+    Failed = 10
+    Unknown
+}
+
+const i32 MaxSize = 0x10
+
+service UserService extends base.BaseService {
+    oneway void Ping()
+    User GetUser(1: i64 id) throws (1: base.Error err)
+}
+`
+	psr := &parser.PEGParser{}
+	doc, errs := psr.Parse("user.thrift", []byte(content))
+	assert.Len(t, errs, 0)
+
+	file := DumpDocument(doc)
+
+	assert.Equal(t, []*Include{{Path: "base.thrift", Prefix: "base", Range: file.Includes[0].Range}}, file.Includes)
+	assert.Equal(t, "go", file.Namespaces[0].Scope)
+	assert.Equal(t, "user", file.Namespaces[0].Name)
+	assert.Len(t, file.Definitions, 4)
+
+	user := file.Definitions[0]
+	assert.Equal(t, KindStruct, user.Kind)
+	assert.Equal(t, "User", user.Name)
+	assert.Equal(t, "User info", user.Doc)
+	assert.Equal(t, &Field{
+		ID:           1,
+		Requiredness: RequirednessRequired,
+		Type:         &Type{Name: "i64"},
+		Name:         "id",
+		Annotations:  []*Annotation{{Key: "api.path", Value: "id"}},
+		Range:        user.Fields[0].Range,
+	}, user.Fields[0])
+	assert.Equal(t, &Field{
+		ID:           2,
+		Requiredness: RequirednessOptional,
+		Type:         &Type{Name: "list", ElemType: &Type{Name: "string"}},
+		Name:         "tags",
+		Default:      &ConstValue{Kind: ConstKindList, Items: []*ConstValue{{Kind: ConstKindString, Value: "a"}}},
+		Doc:          "tags of user",
+		Range:        user.Fields[1].Range,
+	}, user.Fields[1])
+	assert.Equal(t, RequirednessDefault, user.Fields[2].Requiredness)
+	assert.Equal(t, &Type{Name: "map", KeyType: &Type{Name: "string"}, ValueType: &Type{Name: "base.Item"}}, user.Fields[2].Type)
+
+	status := file.Definitions[1]
+	assert.Equal(t, KindEnum, status.Kind)
+	assert.Equal(t, []int64{0, 10, 11}, []int64{status.Values[0].Value, status.Values[1].Value, status.Values[2].Value})
+	assert.Equal(t, []bool{false, true, false}, []bool{status.Values[0].Explicit, status.Values[1].Explicit, status.Values[2].Explicit})
+
+	cst := file.Definitions[2]
+	assert.Equal(t, &ConstValue{Kind: ConstKindInt, Value: int64(16), Text: "0x10"}, cst.Value)
+
+	svc := file.Definitions[3]
+	assert.Equal(t, "base.BaseService", svc.Extends)
+	assert.Equal(t, true, svc.Functions[0].Oneway)
+	assert.Equal(t, &Type{Name: "void"}, svc.Functions[0].ReturnType)
+	assert.Equal(t, "base.Error", svc.Functions[1].Throws[0].Type.Name)
+	assert.Equal(t, Range{
+		Start: Position{Line: 23, Col: 0, Offset: 361},
+		End:   Position{Line: 23, Col: 55, Offset: 416},
+	}, svc.Functions[1].Range)
+}
+
+func TestDump(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "common"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "common", "base.thrift"), []byte(`
+exception Error {
+    1: string message
+}`), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "user.thrift"), []byte(`
+include "common/base.thrift"
+include "missing.thrift"
+
+struct User {
+    1: i64 id
+    2 string name
+}
+
+struct Empty {}
+`), os.ModePerm))
+
+	schema, err := Dump(filepath.Join(dir, "user.thrift"), false)
+	assert.NoError(t, err)
+	assert.Len(t, schema.Files, 1)
+	assert.Equal(t, "", schema.Files[0].Includes[0].ResolvedPath)
+	// syntax error is recovered
+	assert.Len(t, schema.Files[0].Errors, 1)
+	assert.Len(t, schema.Files[0].Definitions, 2)
+
+	schema, err = Dump(filepath.Join(dir, "user.thrift"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, SchemaVersion, schema.Version)
+	assert.Len(t, schema.Files, 2)
+	assert.Equal(t, filepath.Join(dir, "common", "base.thrift"), schema.Files[0].Includes[0].ResolvedPath)
+	assert.Equal(t, "", schema.Files[0].Includes[1].ResolvedPath)
+	assert.Len(t, schema.Files[0].Errors, 2)
+	assert.Equal(t, filepath.Join(dir, "common", "base.thrift"), schema.Files[1].Filename)
+	assert.Equal(t, KindException, schema.Files[1].Definitions[0].Kind)
+	assert.Equal(t, "Error", schema.Files[1].Definitions[0].Name)
+}
@@ -0,0 +1,168 @@
+package dump
+
+// SchemaVersion is increased when json schema has incompatible changes
+const SchemaVersion = 1
+
+type Schema struct {
+	Version int     `json:"version"`
+	Files   []*File `json:"files"`
+}
+
+type File struct {
+	Filename    string        `json:"filename"`
+	Includes    []*Include    `json:"includes"`
+	CPPIncludes []string      `json:"cppIncludes"`
+	Namespaces  []*Namespace  `json:"namespaces"`
+	Definitions []*Definition `json:"definitions"`
+	// Errors are syntax errors or include errors. Definitions are still dumped
+	// if parser can recover from syntax errors
+	Errors []*Error `json:"errors"`
+}
+
+type Include struct {
+	Path string `json:"path"`
+	// Prefix is used to reference definitions in included file, like `base` in `base.User`
+	Prefix string `json:"prefix"`
+	// ResolvedPath is file path of included file. Only set when includes are resolved
+	ResolvedPath string `json:"resolvedPath,omitempty"`
+	Range        Range  `json:"range"`
+}
+
+type Namespace struct {
+	Scope       string        `json:"scope"`
+	Name        string        `json:"name"`
+	Annotations []*Annotation `json:"annotations,omitempty"`
+	Range       Range         `json:"range"`
+}
+
+const (
+	KindStruct    = "struct"
+	KindUnion     = "union"
+	KindException = "exception"
+	KindEnum      = "enum"
+	KindService   = "service"
+	KindConst     = "const"
+	KindTypedef   = "typedef"
+)
+
+type Definition struct {
+	// Kind is one of struct, union, exception, enum, service, const, typedef
+	Kind        string        `json:"kind"`
+	Name        string        `json:"name"`
+	Doc         string        `json:"doc,omitempty"`
+	Annotations []*Annotation `json:"annotations,omitempty"`
+	Range       Range         `json:"range"`
+
+	// struct, union, exception
+	Fields []*Field `json:"fields,omitempty"`
+	// enum
+	Values []*EnumValue `json:"values,omitempty"`
+	// service
+	Extends   string      `json:"extends,omitempty"`
+	Functions []*Function `json:"functions,omitempty"`
+	// const, typedef
+	Type *Type `json:"type,omitempty"`
+	// const
+	Value *ConstValue `json:"value,omitempty"`
+}
+
+const (
+	RequirednessRequired = "required"
+	RequirednessOptional = "optional"
+	RequirednessDefault  = "default"
+)
+
+type Field struct {
+	ID int `json:"id"`
+	// Requiredness is one of required, optional, default
+	Requiredness string        `json:"requiredness"`
+	Type         *Type         `json:"type"`
+	Name         string        `json:"name"`
+	Default      *ConstValue   `json:"default,omitempty"`
+	Doc          string        `json:"doc,omitempty"`
+	Annotations  []*Annotation `json:"annotations,omitempty"`
+	Range        Range         `json:"range"`
+}
+
+type EnumValue struct {
+	Name string `json:"name"`
+	// Value is explicit value or implicit value computed by parser
+	Value       int64         `json:"value"`
+	Explicit    bool          `json:"explicit"`
+	Doc         string        `json:"doc,omitempty"`
+	Annotations []*Annotation `json:"annotations,omitempty"`
+	Range       Range         `json:"range"`
+}
+
+type Function struct {
+	Name   string `json:"name"`
+	Oneway bool   `json:"oneway"`
+	// ReturnType is {"name": "void"} for void function
+	ReturnType  *Type         `json:"returnType"`
+	Arguments   []*Field      `json:"arguments"`
+	Throws      []*Field      `json:"throws,omitempty"`
+	Doc         string        `json:"doc,omitempty"`
+	Annotations []*Annotation `json:"annotations,omitempty"`
+	Range       Range         `json:"range"`
+}
+
+type Type struct {
+	// Name is base type, container type(list, set, map) or identifier. Identifier
+	// keeps include prefix, like base.User
+	Name string `json:"name"`
+	// list, set
+	ElemType *Type `json:"elemType,omitempty"`
+	// map
+	KeyType     *Type         `json:"keyType,omitempty"`
+	ValueType   *Type         `json:"valueType,omitempty"`
+	Annotations []*Annotation `json:"annotations,omitempty"`
+}
+
+const (
+	ConstKindInt        = "int"
+	ConstKindDouble     = "double"
+	ConstKindString     = "string"
+	ConstKindIdentifier = "identifier"
+	ConstKindList       = "list"
+	ConstKindMap        = "map"
+)
+
+type ConstValue struct {
+	// Kind is one of int, double, string, identifier, list, map
+	Kind string `json:"kind"`
+	// Value is set for int, double, string, identifier
+	Value any `json:"value,omitempty"`
+	// Text is value in source, like 0x10
+	Text string `json:"text,omitempty"`
+	// list
+	Items []*ConstValue `json:"items,omitempty"`
+	// map
+	Entries []*ConstMapEntry `json:"entries,omitempty"`
+}
+
+type ConstMapEntry struct {
+	Key   *ConstValue `json:"key"`
+	Value *ConstValue `json:"value"`
+}
+
+type Annotation struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+	Range   Range  `json:"range"`
+}
+
+// Range uses 1-based line and column, column is counted by unicode characters
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type Position struct {
+	Line   int `json:"line"`
+	Col    int `json:"col"`
+	Offset int `json:"offset"`
+}
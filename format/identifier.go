@@ -9,7 +9,6 @@ import (
 func MustFormatIdentifier(id *parser.Identifier, indent string) string {
 	comments := MustFormatComments(id.Comments, indent)
 	if comments != "" {
-		comments = comments
 		if lineDistance(id.Comments[len(id.Comments)-1], id.Name) >= 1 {
 			comments = comments + "\n"
 		} else {
@@ -0,0 +1,49 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/joyme123/thrift-ls/parser"
+)
+
+// TextStyle is line ending and UTF-8 BOM of source file. Formatter always emits '\n',
+// TextStyle is used to convert formatted result back to the style of source file
+type TextStyle struct {
+	// CRLF is true if "\r\n" is the dominant line ending
+	CRLF bool
+	// BOM is true if file starts with UTF-8 BOM
+	BOM bool
+}
+
+// DetectTextStyle detects dominant line ending and UTF-8 BOM of content
+func DetectTextStyle(content []byte) TextStyle {
+	crlf := bytes.Count(content, []byte("\r\n"))
+	lf := bytes.Count(content, []byte("\n")) - crlf
+
+	return TextStyle{
+		CRLF: crlf > lf,
+		BOM:  bytes.HasPrefix(content, parser.UTF8BOM),
+	}
+}
+
+// Apply converts formatted text to the style. If LineEnding option is not "preserve",
+// line ending is normalized to the option and BOM is removed
+func (s TextStyle) Apply(formatted string) string {
+	switch LineEnding {
+	case LineEndingLF:
+		s = TextStyle{CRLF: false}
+	case LineEndingCRLF:
+		s = TextStyle{CRLF: true}
+	}
+
+	formatted = strings.ReplaceAll(formatted, "\r\n", "\n")
+	if s.CRLF {
+		formatted = strings.ReplaceAll(formatted, "\n", "\r\n")
+	}
+	if s.BOM {
+		formatted = string(parser.UTF8BOM) + formatted
+	}
+
+	return formatted
+}
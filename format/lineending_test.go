@@ -0,0 +1,62 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/joyme123/thrift-ls/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DetectTextStyle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    TextStyle
+	}{
+		{
+			name:    "lf",
+			content: "struct A {\n}\n",
+			want:    TextStyle{},
+		},
+		{
+			name:    "crlf",
+			content: "struct A {\r\n}\r\n",
+			want:    TextStyle{CRLF: true},
+		},
+		{
+			name:    "mixed line ending",
+			content: "struct A {\r\n  1: i32 a\r\n}\n",
+			want:    TextStyle{CRLF: true},
+		},
+		{
+			name:    "bom",
+			content: "\xEF\xBB\xBFstruct A {\n}\n",
+			want:    TextStyle{BOM: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectTextStyle([]byte(tt.content)))
+		})
+	}
+}
+
+func Test_FormatDocumentPreserveTextStyle(t *testing.T) {
+	doc := "\xEF\xBB\xBF// user\r\nstruct User {\r\n  1: i64 id\r\n}\r\n"
+
+	psr := &parser.PEGParser{}
+	ast, errs := psr.Parse("test.thrift", []byte(doc))
+	assert.Len(t, errs, 0)
+
+	formatted, err := FormatDocument(ast)
+	assert.NoError(t, err)
+
+	style := DetectTextStyle([]byte(doc))
+	assert.Equal(t, "\xEF\xBB\xBF// user\r\nstruct User {\r\n    1: i64 id\r\n}", style.Apply(formatted))
+
+	LineEnding = LineEndingLF
+	defer func() {
+		LineEnding = LineEndingPreserve
+	}()
+	assert.Equal(t, "// user\nstruct User {\n    1: i64 id\n}", style.Apply(formatted))
+}
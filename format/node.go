@@ -0,0 +1,57 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joyme123/thrift-ls/parser"
+)
+
+// FormatNode formats single ast node with the same options as FormatDocument.
+// It can be used to print nodes constructed by parser builders, which don't have
+// source locations.
+func FormatNode(node parser.Node) (string, error) {
+	if node.IsBadNode() || node.ChildrenBadNode() {
+		return "", BadNodeError
+	}
+
+	res := ""
+	switch node.Type() {
+	case "Document":
+		return FormatDocument(node.(*parser.Document))
+	case "Include":
+		res = MustFormatInclude(node.(*parser.Include))
+	case "CPPInclude":
+		res = MustFormatCPPInclude(node.(*parser.CPPInclude))
+	case "Namespace":
+		res = MustFormatNamespace(node.(*parser.Namespace))
+	case "Struct":
+		res = MustFormatStruct(node.(*parser.Struct))
+	case "Union":
+		res = MustFormatUnion(node.(*parser.Union))
+	case "Exception":
+		res = MustFormatException(node.(*parser.Exception))
+	case "Service":
+		res = MustFormatService(node.(*parser.Service))
+	case "Typedef":
+		res = MustFormatTypedef(node.(*parser.Typedef))
+	case "Const":
+		res = MustFormatConst(node.(*parser.Const))
+	case "Enum":
+		res = MustFormatEnum(node.(*parser.Enum))
+	case "Function":
+		res = MustFormatFunction(node.(*parser.Function), "")
+	case "Field":
+		res = MustFormatField(node.(*parser.Field), " ", "", false)
+	case "EnumValue":
+		res = MustFormatEnumValue(node.(*parser.EnumValue), " ", "")
+	case "FieldType":
+		res = MustFormatFieldType(node.(*parser.FieldType))
+	case "ConstValue":
+		res = MustFormatConstValue(node.(*parser.ConstValue), "", false)
+	default:
+		return "", fmt.Errorf("format: unsupported node type %s", node.Type())
+	}
+
+	return strings.TrimSpace(res), nil
+}
@@ -0,0 +1,89 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/joyme123/thrift-ls/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FormatBuiltDocument(t *testing.T) {
+	opts := Options{}
+	opts.InitDefault()
+
+	user := parser.NewStructBuilder("User").
+		Doc("User info").
+		AddField(parser.NewFieldBuilder(1, parser.NewTypeFieldType("i64"), "id").Required().Build()).
+		AddField(parser.NewFieldBuilder(2, parser.NewTypeFieldType("string"), "name").Optional().Default(parser.NewStringConstValue("anonymous")).Build()).
+		AddField(parser.NewFieldBuilder(3, parser.NewMapFieldType(parser.NewTypeFieldType("string"), parser.NewListFieldType(parser.NewTypeFieldType("i32"))), "tags").Optional().Annotation("go.tag", "json:\\\"tags\\\"").Build()).
+		Build()
+
+	status := parser.NewEnumBuilder("Status").
+		AddValue("OK").
+		AddValueWithNumber("Failed", 10).
+		AddValue("Unknown").
+		Build()
+
+	svc := parser.NewServiceBuilder("UserService").
+		Extends("base.BaseService").
+		AddFunction(parser.NewFunctionBuilder("GetUser", parser.NewTypeFieldType("User")).
+			AddArgument(parser.NewFieldBuilder(1, parser.NewTypeFieldType("i64"), "id").Build()).
+			AddArgument(parser.NewFieldBuilder(2, parser.NewTypeFieldType("bool"), "withTags").Build()).
+			AddThrows(parser.NewFieldBuilder(1, parser.NewTypeFieldType("base.Error"), "err").Build()).
+			Build()).
+		AddFunction(parser.NewFunctionBuilder("Ping", nil).Oneway().Build()).
+		Build()
+
+	doc := parser.NewDocumentBuilder("user.thrift").
+		AddInclude("base.thrift").
+		AddNamespace("go", "user").
+		AddDefinition(parser.NewConstBuilder(parser.NewListFieldType(parser.NewTypeFieldType("i32")), "IDs",
+			parser.NewListConstValue(parser.NewIntConstValue(1), parser.NewIntConstValue(2))).Build()).
+		AddDefinition(parser.NewConstBuilder(parser.NewMapFieldType(parser.NewTypeFieldType("string"), parser.NewTypeFieldType("double")), "Rates",
+			parser.NewConstMapBuilder().Put(parser.NewStringConstValue("a"), parser.NewDoubleConstValue(1)).Build()).Build()).
+		AddDefinition(parser.NewTypedefBuilder(parser.NewTypeFieldType("i64"), "UserID").Build()).
+		AddDefinition(user).
+		AddDefinition(status).
+		AddDefinition(svc).
+		Build()
+
+	expected := `include "base.thrift"
+
+namespace go user
+
+const list<i32> IDs = [1, 2]
+const map<string,double> Rates = {"a": 1.0}
+typedef i64 UserID
+
+/** User info */
+struct User {
+    1: required i64                   id
+    2: optional string                name = "anonymous"
+    3: optional map<string,list<i32>> tags (go.tag = "json:\"tags\"")
+}
+
+enum Status {
+    OK
+    Failed = 10
+    Unknown
+}
+
+service UserService extends base.BaseService {
+    User GetUser(1: i64 id, 2: bool withTags) throws (1: base.Error err)
+    oneway void Ping()
+}`
+
+	formated, err := FormatNode(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, formated)
+
+	ast, err := parser.Parse("user.thrift", []byte(formated))
+	assert.NoError(t, err)
+	assert.True(t, doc.Equals(ast.(*parser.Document)))
+	assert.Equal(t, int64(11), ast.(*parser.Document).Enums[0].Values[2].Value)
+	assert.Equal(t, int64(11), status.Values[2].Value)
+
+	fnStr, err := FormatNode(svc.Functions[1])
+	assert.NoError(t, err)
+	assert.Equal(t, "oneway void Ping()", fnStr)
+}
@@ -9,6 +9,7 @@ import (
 var Indent = "    "
 var Align = "field"
 var FieldLineComma = "disable"
+var LineEnding = "preserve"
 
 type Options struct {
 	// Do not print reformatted sources to standard output.
@@ -35,6 +36,13 @@ type Options struct {
 	// if choose disable, user input will be retained without modification
 	// Default is "disable" if not set
 	FieldLineComma string `yaml:"fieldLineComma"`
+
+	// LineEnding represents line ending of formatted file.
+	// Options: "preserve", "lf", "crlf"
+	// if choose preserve, the dominant line ending and UTF-8 BOM of input are retained.
+	// otherwise line ending is normalized and BOM is removed
+	// Default is "preserve" if not set
+	LineEnding string `yaml:"lineEnding"`
 }
 
 func (o *Options) SetFlags() {
@@ -43,6 +51,7 @@ func (o *Options) SetFlags() {
 	flag.StringVar(&o.Indent, "indent", "4spaces", "Indent to use. Support: num*space, num*tab. example: 4spaces, 1tab, tab")
 	flag.StringVar(&o.Align, "align", "field", `Align enables align option for struct/enum/exception/union fields, Options: "field", "assign", "disable", Default is "field" if not set.`)
 	flag.StringVar(&o.FieldLineComma, "fieldLineComma", "disable", `FieldLineComma enables whether to add or remove comma at end of field line. Options: "add", "remove", "disable". If choose disable, user input will be retained without modification. Default is "disable" if not set`)
+	flag.StringVar(&o.LineEnding, "lineEnding", "preserve", `LineEnding of formatted file. Options: "preserve", "lf", "crlf". If choose preserve, line ending and UTF-8 BOM of input will be retained. Otherwise line ending is normalized and BOM is removed. Default is "preserve" if not set`)
 }
 
 func (o *Options) InitDefault() {
@@ -62,6 +71,15 @@ func (o *Options) InitDefault() {
 	}
 
 	FieldLineComma = o.FieldLineComma
+
+	if o.LineEnding == "" ||
+		(o.LineEnding != LineEndingPreserve &&
+			o.LineEnding != LineEndingLF &&
+			o.LineEnding != LineEndingCRLF) {
+		o.LineEnding = "preserve"
+	}
+
+	LineEnding = o.LineEnding
 }
 
 func (o *Options) GetIndent() string {
@@ -21,6 +21,12 @@ const (
 	FieldLineCommaDisable = "disable"
 )
 
+const (
+	LineEndingPreserve = "preserve"
+	LineEndingLF       = "lf"
+	LineEndingCRLF     = "crlf"
+)
+
 func MustFormat(tplText string, formatter any) string {
 	tpl, err := template.New("default").Parse(tplText)
 	if err != nil {
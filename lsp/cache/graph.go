@@ -54,6 +54,30 @@ func (g *IncludeGraph) Get(file uri.URI) *IncludeNode {
 	return g.mapper[file]
 }
 
+// IncludedBy returns file and files including it directly or indirectly. file is the first one
+func (g *IncludeGraph) IncludedBy(file uri.URI) []uri.URI {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	res := []uri.URI{file}
+	visited := map[uri.URI]struct{}{file: {}}
+	for i := 0; i < len(res); i++ {
+		node := g.mapper[res[i]]
+		if node == nil {
+			continue
+		}
+		for _, in := range node.indegree {
+			if _, ok := visited[in]; ok {
+				continue
+			}
+			visited[in] = struct{}{}
+			res = append(res, in)
+		}
+	}
+
+	return res
+}
+
 func (g *IncludeGraph) Set(file uri.URI, includes []*parser.Include) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
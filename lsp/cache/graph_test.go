@@ -75,3 +75,20 @@ func Test_Graph(t *testing.T) {
 	assert.Nil(t, graph.Get("file:///tmp/base.thrift"), "base.thrift")
 	assert.Nil(t, graph.Get("file:///tmp/addr.thrift"), "addr.thrift")
 }
+
+func Test_GraphIncludedBy(t *testing.T) {
+	graph := NewIncludeGraph()
+
+	// user.thrift includes addr.thrift, and addr.thrift includes base.thrift
+	graph.Set("file:///tmp/user.thrift", []*parser.Include{
+		{Path: &parser.Literal{Value: &parser.LiteralValue{Text: "./addr.thrift"}}},
+	})
+	graph.Set("file:///tmp/addr.thrift", []*parser.Include{
+		{Path: &parser.Literal{Value: &parser.LiteralValue{Text: "./base.thrift"}}},
+	})
+	graph.Set("file:///tmp/base.thrift", nil)
+
+	assert.Equal(t, []uri.URI{"file:///tmp/base.thrift", "file:///tmp/addr.thrift", "file:///tmp/user.thrift"},
+		graph.IncludedBy("file:///tmp/base.thrift"))
+	assert.Equal(t, []uri.URI{"file:///tmp/user.thrift"}, graph.IncludedBy("file:///tmp/user.thrift"))
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/joyme123/thrift-ls/lsp/mapper"
@@ -15,6 +16,8 @@ type ParseCaches struct {
 	mu     sync.RWMutex
 	caches map[uri.URI]*ParsedFile
 	tokens map[string]struct{}
+	// symbols is index of definitions in caches, it is built lazily
+	symbols []*Symbol
 }
 
 func NewParseCaches() *ParseCaches {
@@ -27,6 +30,7 @@ func (c *ParseCaches) Set(filePath uri.URI, res *ParsedFile) {
 	c.mu.Lock()
 	c.caches[filePath] = res
 	c.tokens = nil
+	c.symbols = nil
 	c.mu.Unlock()
 }
 
@@ -42,6 +46,22 @@ func (c *ParseCaches) Forget(filePath uri.URI) {
 
 	delete(c.caches, filePath)
 	c.tokens = nil
+	c.symbols = nil
+}
+
+// Files returns parsed files in order
+func (c *ParseCaches) Files() []uri.URI {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	files := make([]uri.URI, 0, len(c.caches))
+	for file := range c.caches {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i] < files[j]
+	})
+	return files
 }
 
 func (c *ParseCaches) Clone() *ParseCaches {
@@ -215,7 +235,8 @@ func Parse(fh FileHandle) (*ParsedFile, error) {
 		log.Debugf("peg parsed err: %v", errs)
 	}
 
-	mp := mapper.NewMapper(fh.URI(), content)
+	// ast positions are relative to content without BOM
+	mp := mapper.NewMapper(fh.URI(), parser.StripBOM(content))
 	pf.mapper = mp
 
 	return pf, nil
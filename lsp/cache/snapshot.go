@@ -59,6 +59,11 @@ func (s *Snapshot) Graph() *IncludeGraph {
 	return s.graph
 }
 
+// ContainsFile reports whether file is in workspace folder
+func (s *Snapshot) ContainsFile(file uri.URI) bool {
+	return s.view.ContainsFile(file)
+}
+
 func (s *Snapshot) ReadFile(ctx context.Context, uri uri.URI) (FileHandle, error) {
 	log.Debugln("snapshot read file", uri)
 	s.view.MarkFileKnown(uri)
@@ -117,6 +122,16 @@ func (s *Snapshot) Tokens() map[string]struct{} {
 	return s.parsedCache.Tokens()
 }
 
+// Files returns all parsed files, including files walked at initialization
+func (s *Snapshot) Files() []uri.URI {
+	return s.parsedCache.Files()
+}
+
+// Symbols returns workspace symbol index built from all parsed files
+func (s *Snapshot) Symbols() []*Symbol {
+	return s.parsedCache.Symbols()
+}
+
 func (s *Snapshot) clone() (*Snapshot, func()) {
 	snap := &Snapshot{
 		id:   rand.Int63(),
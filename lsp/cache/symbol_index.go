@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// kinds of Symbol
+const (
+	SymbolStruct    = "struct"
+	SymbolUnion     = "union"
+	SymbolException = "exception"
+	SymbolEnum      = "enum"
+	SymbolEnumValue = "enum value"
+	SymbolTypedef   = "typedef"
+	SymbolConst     = "const"
+	SymbolService   = "service"
+)
+
+// Symbol is a definition which can be referred by other files
+type Symbol struct {
+	// Name is name of definition. Name of enum value is like `Status.OK`
+	Name string
+	Kind string
+	File uri.URI
+	// Node is the definition node, Value of enum value is recorded in it
+	Node parser.Node
+	Doc  string
+}
+
+// DocumentSymbols returns definitions in ast in declaration order
+func DocumentSymbols(file uri.URI, ast *parser.Document) []*Symbol {
+	var res []*Symbol
+	add := func(kind string, id *parser.Identifier, node parser.Node, doc string) {
+		if id == nil || id.BadNode || id.Name == nil {
+			return
+		}
+		res = append(res, &Symbol{Name: id.Name.Text, Kind: kind, File: file, Node: node, Doc: doc})
+	}
+
+	for _, node := range ast.Nodes {
+		if node.IsBadNode() {
+			continue
+		}
+		switch n := node.(type) {
+		case *parser.Struct:
+			add(SymbolStruct, n.Identifier, n, n.Doc())
+		case *parser.Union:
+			add(SymbolUnion, n.Name, n, n.Doc())
+		case *parser.Exception:
+			add(SymbolException, n.Name, n, n.Doc())
+		case *parser.Enum:
+			add(SymbolEnum, n.Name, n, n.Doc())
+			if n.Name == nil || n.Name.Name == nil {
+				continue
+			}
+			for _, v := range n.Values {
+				if v.BadNode || v.Name == nil || v.Name.Name == nil {
+					continue
+				}
+				res = append(res, &Symbol{Name: n.Name.Name.Text + "." + v.Name.Name.Text, Kind: SymbolEnumValue, File: file, Node: v, Doc: v.Doc()})
+			}
+		case *parser.Typedef:
+			add(SymbolTypedef, n.Alias, n, n.Doc())
+		case *parser.Const:
+			add(SymbolConst, n.Name, n, n.Doc())
+		case *parser.Service:
+			add(SymbolService, n.Name, n, n.Doc())
+		}
+	}
+
+	return res
+}
+
+// Symbols returns definitions of all parsed files, including files walked at initialization.
+// Symbols are sorted by file
+func (c *ParseCaches) Symbols() []*Symbol {
+	c.mu.RLock()
+	if c.symbols != nil {
+		defer c.mu.RUnlock()
+		return c.symbols
+	}
+	files := make([]uri.URI, 0, len(c.caches))
+	for file := range c.caches {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i] < files[j]
+	})
+
+	symbols := make([]*Symbol, 0)
+	for _, file := range files {
+		if ast := c.caches[file].ast; ast != nil {
+			symbols = append(symbols, DocumentSymbols(file, ast)...)
+		}
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	c.symbols = symbols
+	c.mu.Unlock()
+
+	return symbols
+}
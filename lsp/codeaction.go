@@ -0,0 +1,12 @@
+package lsp
+
+import (
+	"context"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/diagnostic"
+)
+
+func (s *Server) codeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
+	return diagnostic.QuickFixes(params.TextDocument.URI, params.Context.Diagnostics), nil
+}
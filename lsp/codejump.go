@@ -19,6 +19,18 @@ func (s *Server) definition(ctx context.Context, params *protocol.DefinitionPara
 	return codejump.Definition(ctx, ss, params.TextDocument.URI, params.Position)
 }
 
+func (s *Server) declaration(ctx context.Context, params *protocol.DeclarationParams) (result []protocol.Location, err error) {
+	file := params.TextDocument.URI
+	view, err := s.session.ViewOf(file)
+	if err != nil {
+		return nil, err
+	}
+	ss, release := view.Snapshot()
+	defer release()
+
+	return codejump.Declaration(ctx, ss, params.TextDocument.URI, params.Position)
+}
+
 func (s *Server) references(ctx context.Context, params *protocol.ReferenceParams) (result []protocol.Location, err error) {
 	file := params.TextDocument.URI
 	view, err := s.session.ViewOf(file)
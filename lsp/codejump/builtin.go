@@ -0,0 +1,26 @@
+package codejump
+
+// BuiltinDoc returns documentation of base type or keyword. It returns empty string if there is none
+func BuiltinDoc(name string) string {
+	return builtinDocs[name]
+}
+
+// builtinDocs are documentations of base types and keywords
+var builtinDocs = map[string]string{
+	"bool":     "A boolean value, true or false.",
+	"byte":     "An 8-bit signed integer. It is the same as `i8`.",
+	"i8":       "An 8-bit signed integer.",
+	"i16":      "A 16-bit signed integer.",
+	"i32":      "A 32-bit signed integer.",
+	"i64":      "A 64-bit signed integer.",
+	"double":   "A 64-bit floating point number.",
+	"string":   "A text string encoded using UTF-8 encoding.",
+	"binary":   "A sequence of unencoded bytes.",
+	"uuid":     "A 128-bit universally unique identifier.",
+	"void":     "Function returns nothing.",
+	"list":     "An ordered list of elements, like `list<i32>`.",
+	"set":      "An unordered set of unique elements, like `set<string>`.",
+	"map":      "A map of strictly unique keys to values, like `map<string, i32>`.",
+	"required": "Field must be set when writing and is checked when reading.",
+	"optional": "Field may be unset, it is written only if it is set.",
+}
@@ -32,18 +32,276 @@ func Definition(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos proto
 	nodePath := parser.SearchNodePathByPosition(pf.AST(), astPos)
 	targetNode := nodePath[len(nodePath)-1]
 
+	// include name part of qualified name jumps to the include
+	if inc := includeOfQualifiedName(file, pf.AST(), nodePath, astPos, true); inc != nil {
+		return append(res, jump(file, inc)), nil
+	}
+
 	switch targetNode.Type() {
 	case "TypeName":
 		return typeNameDefinition(ctx, ss, file, pf.AST(), targetNode)
 	case "ConstValue":
-		return constValueTypeDefinition(ctx, ss, file, pf.AST(), targetNode)
-	case "IdentifierName": // service extends
+		cv := innermostConstValue(targetNode.(*parser.ConstValue), astPos)
+		if cv.TypeName == "string" {
+			// field name in struct literal
+			return constValueFieldDefinition(ctx, ss, file, pf.AST(), nodePath[len(nodePath)-2], targetNode.(*parser.ConstValue), cv)
+		}
+		return constValueTypeDefinition(ctx, ss, file, pf.AST(), cv)
+	case "IdentifierName":
+		if len(nodePath) >= 4 {
+			if fn, ok := nodePath[len(nodePath)-3].(*parser.Function); ok {
+				return functionDefinition(ctx, ss, file, pf.AST(), nodePath[len(nodePath)-4], fn)
+			}
+		}
+		// service extends
 		return serviceDefinition(ctx, ss, file, pf.AST(), targetNode)
+	case "LiteralValue":
+		// literalValue -> literal -> include
+		if len(nodePath) >= 3 {
+			if inc, ok := nodePath[len(nodePath)-3].(*parser.Include); ok {
+				return includeDefinition(ctx, ss, file, inc)
+			}
+		}
+	}
+
+	return
+}
+
+// Declaration jumps from a qualified usage like `base.User` to the include bringing it in
+func Declaration(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protocol.Position) (res []protocol.Location, err error) {
+	res = make([]protocol.Location, 0)
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return
+	}
+
+	if pf.AST() == nil {
+		err = errors.New("parse ast failed")
+		return
+	}
+
+	astPos, err := pf.Mapper().LSPPosToParserPosition(types.Position{Line: pos.Line, Character: pos.Character})
+	if err != nil {
+		return
+	}
+	nodePath := parser.SearchNodePathByPosition(pf.AST(), astPos)
+
+	if inc := includeOfQualifiedName(file, pf.AST(), nodePath, astPos, false); inc != nil {
+		res = append(res, jump(file, inc))
 	}
 
 	return
 }
 
+// includeOfQualifiedName returns include of type name, const value or extends at pos which is
+// qualified by include name. If onPrefix is true, pos must be on the include name part
+func includeOfQualifiedName(file uri.URI, ast *parser.Document, nodePath []parser.Node, pos parser.Position, onPrefix bool) *parser.Include {
+	targetNode := nodePath[len(nodePath)-1]
+
+	var name string
+	switch n := targetNode.(type) {
+	case *parser.TypeName:
+		name = n.Name
+	case *parser.ConstValue:
+		targetNode = innermostConstValue(n, pos)
+		if cv := targetNode.(*parser.ConstValue); cv.TypeName == "identifier" {
+			name, _ = cv.Value.(string)
+		}
+	case *parser.IdentifierName:
+		if len(nodePath) < 3 {
+			return nil
+		}
+		if svc, ok := nodePath[len(nodePath)-3].(*parser.Service); ok && svc.Extends != nil && svc.Extends.Name == n {
+			name = n.Text
+		}
+	}
+
+	include, _ := lsputils.ParseIdent(file, ast.Includes, name)
+	if include == "" {
+		return nil
+	}
+	if onPrefix && pos.Offset-targetNode.Pos().Offset >= len(include) {
+		return nil
+	}
+
+	return GetIncludeNode(file, ast, include)
+}
+
+// innermostConstValue returns const value in list or map at pos
+func innermostConstValue(cv *parser.ConstValue, pos parser.Position) *parser.ConstValue {
+	var items []*parser.ConstValue
+	switch cv.TypeName {
+	case "list", "map":
+		items, _ = cv.Value.([]*parser.ConstValue)
+	case "pair":
+		key, _ := cv.Key.(*parser.ConstValue)
+		value, _ := cv.Value.(*parser.ConstValue)
+		items = []*parser.ConstValue{key, value}
+	}
+
+	for _, item := range items {
+		if item == nil || item.BadNode {
+			continue
+		}
+		if item.Pos().Offset <= pos.Offset && pos.Offset < item.End().Offset {
+			return innermostConstValue(item, pos)
+		}
+	}
+
+	return cv
+}
+
+// includeDefinition jumps to the beginning of included file
+func includeDefinition(ctx context.Context, ss *cache.Snapshot, file uri.URI, inc *parser.Include) ([]protocol.Location, error) {
+	res := make([]protocol.Location, 0)
+	if inc.BadNode || inc.Path == nil || inc.Path.Value == nil {
+		return res, nil
+	}
+
+	includeURI := lsputils.IncludeURI(file, inc.Path.Value.Text)
+	fh, err := ss.ReadFile(ctx, includeURI)
+	if err != nil {
+		return res, err
+	}
+	if _, err := fh.Content(); err != nil {
+		// file doesn't exist
+		return res, nil
+	}
+
+	return append(res, protocol.Location{URI: includeURI}), nil
+}
+
+// functionDefinition jumps to the declaration of function in the nearest service extended by its service
+func functionDefinition(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, owner parser.Node, fn *parser.Function) ([]protocol.Location, error) {
+	res := make([]protocol.Location, 0)
+	svc, ok := owner.(*parser.Service)
+	if !ok || fn.Name == nil || fn.Name.Name == nil {
+		return res, nil
+	}
+
+	err := walkExtendedServices(ctx, ss, file, ast, svc, func(parentFile uri.URI, _ *parser.Document, parent *parser.Service) {
+		if len(res) > 0 {
+			return
+		}
+		if function := serviceFunction(parent, fn.Name.Name.Text); function != nil {
+			res = append(res, jump(parentFile, function.Name.Name))
+		}
+	})
+
+	return res, err
+}
+
+// constValueFieldDefinition jumps from key of struct literal to field of struct. owner is const or
+// field whose value is cv, key is the string const value at cursor
+func constValueFieldDefinition(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, owner parser.Node, cv *parser.ConstValue, key *parser.ConstValue) ([]protocol.Location, error) {
+	res := make([]protocol.Location, 0)
+
+	var ft *parser.FieldType
+	switch n := owner.(type) {
+	case *parser.Const:
+		ft = n.ConstType
+	case *parser.Field:
+		ft = n.FieldType
+	default:
+		return res, nil
+	}
+
+	definitionFile, field, err := constValueKeyField(ctx, ss, file, ast, ft, cv, key)
+	if err != nil || field == nil {
+		return res, err
+	}
+
+	return append(res, jump(definitionFile, field.Identifier.Name)), nil
+}
+
+// constValueKeyField finds field named by key in struct literals of cv. ft is type of cv written in file
+func constValueKeyField(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, ft *parser.FieldType, cv *parser.ConstValue, key *parser.ConstValue) (uri.URI, *parser.Field, error) {
+	if ft == nil || cv == nil || cv.BadNode {
+		return "", nil, nil
+	}
+	resolved, err := ResolveFieldType(ctx, ss, file, ast, ft)
+	if err != nil || resolved == nil {
+		return "", nil, err
+	}
+
+	values, _ := cv.Value.([]*parser.ConstValue)
+	var fields []*parser.Field
+	switch n := resolved.Definition.(type) {
+	case *parser.Struct:
+		fields = n.Fields
+	case *parser.Union:
+		fields = n.Fields
+	case *parser.Exception:
+		fields = n.Fields
+	case nil:
+		// container types
+		for _, item := range values {
+			if item == nil || item.BadNode {
+				continue
+			}
+			var definitionFile uri.URI
+			var field *parser.Field
+			if item.TypeName == "pair" {
+				k, _ := item.Key.(*parser.ConstValue)
+				v, _ := item.Value.(*parser.ConstValue)
+				definitionFile, field, err = constValueKeyField(ctx, ss, resolved.File, resolved.AST, resolved.FieldType.KeyType, k, key)
+				if err != nil || field != nil {
+					return definitionFile, field, err
+				}
+				definitionFile, field, err = constValueKeyField(ctx, ss, resolved.File, resolved.AST, resolved.FieldType.ValueType, v, key)
+			} else {
+				// element type of list and set is recorded as KeyType
+				definitionFile, field, err = constValueKeyField(ctx, ss, resolved.File, resolved.AST, resolved.FieldType.KeyType, item, key)
+			}
+			if err != nil || field != nil {
+				return definitionFile, field, err
+			}
+		}
+		return "", nil, nil
+	default:
+		return "", nil, nil
+	}
+	if cv.TypeName != "map" {
+		return "", nil, nil
+	}
+
+	for _, pair := range values {
+		if pair == nil || pair.BadNode || pair.TypeName != "pair" {
+			continue
+		}
+		k, _ := pair.Key.(*parser.ConstValue)
+		v, _ := pair.Value.(*parser.ConstValue)
+		if k == nil || k.BadNode {
+			continue
+		}
+		literal, ok := k.Value.(*parser.Literal)
+		if !ok || literal.Value == nil {
+			continue
+		}
+
+		var field *parser.Field
+		for _, f := range fields {
+			if !f.BadNode && f.Identifier != nil && f.Identifier.Name != nil && f.Identifier.Name.Text == literal.Value.Text {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			continue
+		}
+		if k == key {
+			return resolved.DefinitionFile, field, nil
+		}
+
+		definitionFile, nested, err := constValueKeyField(ctx, ss, resolved.DefinitionFile, resolved.DefinitionAST, field.FieldType, v, key)
+		if err != nil || nested != nil {
+			return definitionFile, nested, err
+		}
+	}
+
+	return "", nil, nil
+}
+
 func serviceDefinition(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, targetNode parser.Node) ([]protocol.Location, error) {
 	res := make([]protocol.Location, 0)
 	astFile, id, _, err := ServiceDefinitionIdentifier(ctx, ss, file, ast, targetNode)
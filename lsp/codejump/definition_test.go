@@ -216,7 +216,7 @@ struct Person {
 				file: "file:///tmp/api.thrift",
 				pos: protocol.Position{
 					Line:      3,
-					Character: 7,
+					Character: 12,
 				},
 			},
 			want: []protocol.Location{
@@ -357,3 +357,102 @@ struct Person {
 		})
 	}
 }
+
+func TestDefinition_Member(t *testing.T) {
+	base := `struct Info {
+  1: string city
+}
+struct User {
+  1: string name
+  2: Info info
+}
+service BaseService {
+  void ping()
+}`
+
+	api := `include "base.thrift"
+const base.User user = {"name": "a", "info": {"city": "x"}}
+service Api extends base.BaseService {
+  void ping()
+}
+service Ext extends Api {
+  void ping()
+}`
+
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{URI: "file:///tmp/base.thrift", Content: []byte(base), From: cache.FileChangeTypeDidOpen},
+		{URI: "file:///tmp/api.thrift", Content: []byte(api), From: cache.FileChangeTypeDidOpen},
+	})
+
+	location := func(file uri.URI, startLine, start, endLine, end uint32) protocol.Location {
+		return protocol.Location{
+			URI: file,
+			Range: protocol.Range{
+				Start: protocol.Position{Line: startLine, Character: start},
+				End:   protocol.Position{Line: endLine, Character: end},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		pos  protocol.Position
+		want []protocol.Location
+	}{
+		{
+			name: "include literal",
+			pos:  protocol.Position{Line: 0, Character: 12},
+			want: []protocol.Location{location("file:///tmp/base.thrift", 0, 0, 0, 0)},
+		},
+		{
+			name: "include name of type",
+			pos:  protocol.Position{Line: 1, Character: 8},
+			want: []protocol.Location{location("file:///tmp/api.thrift", 0, 0, 0, 21)},
+		},
+		{
+			name: "include name of extends",
+			pos:  protocol.Position{Line: 2, Character: 21},
+			want: []protocol.Location{location("file:///tmp/api.thrift", 0, 0, 0, 21)},
+		},
+		{
+			name: "type after include name",
+			pos:  protocol.Position{Line: 1, Character: 12},
+			want: []protocol.Location{location("file:///tmp/base.thrift", 3, 7, 3, 11)},
+		},
+		{
+			name: "field in struct literal",
+			pos:  protocol.Position{Line: 1, Character: 27},
+			want: []protocol.Location{location("file:///tmp/base.thrift", 4, 12, 4, 16)},
+		},
+		{
+			name: "field in nested struct literal",
+			pos:  protocol.Position{Line: 1, Character: 49},
+			want: []protocol.Location{location("file:///tmp/base.thrift", 1, 12, 1, 16)},
+		},
+		{
+			name: "function overridden in the nearest service",
+			pos:  protocol.Position{Line: 6, Character: 8},
+			want: []protocol.Location{location("file:///tmp/api.thrift", 3, 7, 3, 11)},
+		},
+		{
+			name: "function in extended service of other file",
+			pos:  protocol.Position{Line: 3, Character: 8},
+			want: []protocol.Location{location("file:///tmp/base.thrift", 8, 7, 8, 11)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Definition(context.TODO(), ss, "file:///tmp/api.thrift", tt.pos)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := Declaration(context.TODO(), ss, "file:///tmp/api.thrift", protocol.Position{Line: 1, Character: 12})
+	assert.NoError(t, err)
+	assert.Equal(t, []protocol.Location{location("file:///tmp/api.thrift", 0, 0, 0, 21)}, got)
+
+	got, err = Declaration(context.TODO(), ss, "file:///tmp/api.thrift", protocol.Position{Line: 5, Character: 21})
+	assert.NoError(t, err)
+	assert.Equal(t, []protocol.Location{}, got)
+}
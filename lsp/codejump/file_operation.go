@@ -0,0 +1,121 @@
+package codejump
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"go.lsp.dev/uri"
+)
+
+// RenameFiles returns edits updating includes of files to be renamed or moved. Include paths are
+// recomputed relative to the new locations. If base name of a file changes, references qualified
+// by its include name are renamed too. Renamed folders move every file in them
+func RenameFiles(ctx context.Context, ss *cache.Snapshot, renames []protocol.FileRename) (*protocol.WorkspaceEdit, error) {
+	files := ss.Files()
+	moved := make(map[uri.URI]uri.URI)
+	for _, rename := range renames {
+		oldURI, newURI := uri.URI(rename.OldURI), uri.URI(rename.NewURI)
+		for _, file := range files {
+			if file == oldURI {
+				moved[file] = newURI
+			} else if strings.HasPrefix(string(file), string(oldURI)+"/") {
+				moved[file] = newURI + file[len(oldURI):]
+			}
+		}
+	}
+
+	newLocation := func(file uri.URI) uri.URI {
+		if newURI, ok := moved[file]; ok {
+			return newURI
+		}
+		return file
+	}
+
+	res := &protocol.WorkspaceEdit{
+		Changes: make(map[protocol.DocumentURI][]protocol.TextEdit),
+	}
+	for _, file := range files {
+		pf, err := ss.Parse(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		if pf.AST() == nil {
+			continue
+		}
+
+		newFile := newLocation(file)
+		for _, inc := range pf.AST().Includes {
+			if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+				continue
+			}
+			target := lsputils.IncludeURI(file, inc.Path.Value.Text)
+			newTarget := newLocation(target)
+			if newFile == file && newTarget == target {
+				continue
+			}
+
+			if newPath := lsputils.RelativeIncludePath(newFile, newTarget); newPath != inc.Path.Value.Text {
+				res.Changes[file] = append(res.Changes[file], protocol.TextEdit{
+					Range:   lsputils.ASTNodeToRange(inc.Path.Value),
+					NewText: newPath,
+				})
+			}
+
+			newName := lsputils.GetIncludeName(newTarget)
+			if newName == lsputils.GetIncludeName(target) {
+				continue
+			}
+			for _, loc := range searchIncludeReferences(file, pf.AST(), inc) {
+				res.Changes[file] = append(res.Changes[file], protocol.TextEdit{
+					Range:   loc.Range,
+					NewText: newName,
+				})
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteFiles returns warnings of files which will lose includes if files or folders are deleted
+func DeleteFiles(ctx context.Context, ss *cache.Snapshot, deletes []protocol.FileDelete) ([]string, error) {
+	files := ss.Files()
+	deleted := make(map[uri.URI]struct{})
+	for _, del := range deletes {
+		deleteURI := uri.URI(del.URI)
+		for _, file := range files {
+			if file == deleteURI || strings.HasPrefix(string(file), string(deleteURI)+"/") {
+				deleted[file] = struct{}{}
+			}
+		}
+	}
+
+	var res []string
+	for _, file := range files {
+		if _, ok := deleted[file]; ok {
+			continue
+		}
+		pf, err := ss.Parse(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		if pf.AST() == nil {
+			continue
+		}
+
+		for _, inc := range pf.AST().Includes {
+			if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+				continue
+			}
+			if _, ok := deleted[lsputils.IncludeURI(file, inc.Path.Value.Text)]; ok {
+				res = append(res, fmt.Sprintf("%s includes %q which will be deleted", file.Filename(), inc.Path.Value.Text))
+			}
+		}
+	}
+
+	return res, nil
+}
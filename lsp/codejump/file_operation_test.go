@@ -0,0 +1,91 @@
+package codejump
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func TestRenameFiles(t *testing.T) {
+	base := `struct User {
+}`
+	main := `include "../common/base.thrift"
+struct A {
+  1: base.User u
+}`
+
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{URI: "file:///tmp/common/base.thrift", Content: []byte(base), From: cache.FileChangeTypeDidOpen},
+		{URI: "file:///tmp/api/main.thrift", Content: []byte(main), From: cache.FileChangeTypeDidOpen},
+	})
+
+	edit := func(line, start, end uint32, newText string) protocol.TextEdit {
+		return protocol.TextEdit{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			},
+			NewText: newText,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		renames []protocol.FileRename
+		want    map[uri.URI][]protocol.TextEdit
+	}{
+		{
+			name:    "move included file",
+			renames: []protocol.FileRename{{OldURI: "file:///tmp/common/base.thrift", NewURI: "file:///tmp/shared/base.thrift"}},
+			want: map[uri.URI][]protocol.TextEdit{
+				"file:///tmp/api/main.thrift": {edit(0, 9, 30, "../shared/base.thrift")},
+			},
+		},
+		{
+			name:    "rename included file",
+			renames: []protocol.FileRename{{OldURI: "file:///tmp/common/base.thrift", NewURI: "file:///tmp/common/core.thrift"}},
+			want: map[uri.URI][]protocol.TextEdit{
+				"file:///tmp/api/main.thrift": {edit(0, 9, 30, "../common/core.thrift"), edit(2, 5, 9, "core")},
+			},
+		},
+		{
+			name:    "move folder of including file",
+			renames: []protocol.FileRename{{OldURI: "file:///tmp/api", NewURI: "file:///tmp/svc/api"}},
+			want: map[uri.URI][]protocol.TextEdit{
+				"file:///tmp/api/main.thrift": {edit(0, 9, 30, "../../common/base.thrift")},
+			},
+		},
+		{
+			name:    "unrelated file",
+			renames: []protocol.FileRename{{OldURI: "file:///tmp/other.thrift", NewURI: "file:///tmp/another.thrift"}},
+			want:    map[uri.URI][]protocol.TextEdit{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenameFiles(context.TODO(), ss, tt.renames)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Changes)
+		})
+	}
+}
+
+func TestDeleteFiles(t *testing.T) {
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{URI: "file:///tmp/common/base.thrift", Content: []byte("struct User {\n}"), From: cache.FileChangeTypeDidOpen},
+		{URI: "file:///tmp/common/user.thrift", Content: []byte(`include "base.thrift"`), From: cache.FileChangeTypeDidOpen},
+		{URI: "file:///tmp/api/main.thrift", Content: []byte(`include "../common/base.thrift"`), From: cache.FileChangeTypeDidOpen},
+	})
+
+	got, err := DeleteFiles(context.TODO(), ss, []protocol.FileDelete{{URI: "file:///tmp/common"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`/tmp/api/main.thrift includes "../common/base.thrift" which will be deleted`}, got)
+
+	got, err = DeleteFiles(context.TODO(), ss, []protocol.FileDelete{{URI: "file:///tmp/common/user.thrift"}})
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
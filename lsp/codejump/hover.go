@@ -3,6 +3,8 @@ package codejump
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/joyme123/protocol"
 	"github.com/joyme123/thrift-ls/format"
@@ -14,6 +16,8 @@ import (
 	"go.lsp.dev/uri"
 )
 
+// Hover returns hover content in markdown. Definition is shown in thrift code block
+// and followed by its doc comment
 func Hover(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protocol.Position) (res string, err error) {
 	pf, err := ss.Parse(ctx, file)
 	if err != nil {
@@ -39,8 +43,33 @@ func Hover(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protocol.P
 		return hoverDefinition(ctx, ss, file, pf.AST(), targetNode)
 	case "ConstValue":
 		return hoverConstValue(ctx, ss, file, pf.AST(), targetNode)
-	case "IdentifierName": // service extends
-		return hoverService(ctx, ss, file, pf.AST(), targetNode)
+	case "IdentifierName":
+		// parent of identifier
+		if len(nodePath) < 3 {
+			return
+		}
+		switch parent := nodePath[len(nodePath)-3].(type) {
+		case *parser.Field:
+			return hoverField(ctx, ss, file, pf.AST(), nodePath[len(nodePath)-4], parent)
+		case *parser.Function:
+			return hoverFunction(ctx, ss, file, pf.AST(), nodePath[len(nodePath)-4], parent)
+		case *parser.EnumValue:
+			return hoverEnumValue(nodePath[len(nodePath)-4], parent), nil
+		case *parser.Namespace:
+			return hoverNamespace(parent), nil
+		case *parser.Service: // service name and extends
+			return hoverService(ctx, ss, file, pf.AST(), targetNode)
+		}
+	case "LiteralValue":
+		if len(nodePath) >= 3 {
+			if inc, ok := nodePath[len(nodePath)-3].(*parser.Include); ok {
+				return hoverInclude(ctx, ss, file, inc)
+			}
+		}
+	case "VoidKeyword":
+		return HoverContent("void", BuiltinDoc("void")), nil
+	case "NamespaceKeyword":
+		return hoverNamespace(nodePath[len(nodePath)-2].(*parser.Namespace)), nil
 	}
 
 	return
@@ -73,7 +102,7 @@ func hoverService(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *pa
 
 	dstService := GetServiceNode(dstAst.AST(), identifier)
 	if dstService != nil {
-		return format.MustFormatService(dstService), nil
+		return HoverContent(FormatDefinition(dstService), dstService.Doc()), nil
 	}
 
 	return "", nil
@@ -82,8 +111,8 @@ func hoverService(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *pa
 func hoverDefinition(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, targetNode parser.Node) (string, error) {
 	typeName := targetNode.(*parser.TypeName)
 	typeV := typeName.Name
-	if IsBasicType(typeV) {
-		return "", nil
+	if IsBasicType(typeV) || IsContainerType(typeV) {
+		return HoverContent(typeV, BuiltinDoc(typeV)), nil
 	}
 
 	include, identifier := lsputils.ParseIdent(file, ast.Includes, typeV)
@@ -111,23 +140,34 @@ func hoverDefinition(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast
 	// struct, exception, enum or union
 	dstException := GetExceptionNode(dstAst.AST(), identifier)
 	if dstException != nil {
-		return format.MustFormatException(dstException), nil
+		return HoverContent(FormatDefinition(dstException), dstException.Doc()), nil
 	}
 	dstStruct := GetStructNode(dstAst.AST(), identifier)
 	if dstStruct != nil {
-		return format.MustFormatStruct(dstStruct), nil
+		return HoverContent(FormatDefinition(dstStruct), dstStruct.Doc()), nil
 	}
 	dstEnum := GetEnumNode(dstAst.AST(), identifier)
 	if dstEnum != nil {
-		return format.MustFormatEnum(dstEnum), nil
+		return HoverContent(FormatDefinition(dstEnum), dstEnum.Doc()), nil
 	}
 	dstUnion := GetUnionNode(dstAst.AST(), identifier)
 	if dstUnion != nil {
-		return format.MustFormatUnion(dstUnion), nil
+		return HoverContent(FormatDefinition(dstUnion), dstUnion.Doc()), nil
 	}
 	dstTypedef := GetTypedefNode(dstAst.AST(), identifier)
 	if dstTypedef != nil {
-		return format.MustFormatTypedef(dstTypedef), nil
+		doc := dstTypedef.Doc()
+		// show full chain if typedef refers to another typedef
+		chain, err := ResolveTypedefChain(ctx, ss, astFile, dstAst.AST(), dstTypedef)
+		if err == nil && len(chain.Typedefs) > 1 {
+			resolves := "`" + chain.String() + "`"
+			if doc != "" {
+				doc = resolves + "\n\n" + doc
+			} else {
+				doc = resolves
+			}
+		}
+		return HoverContent(FormatDefinition(dstTypedef), doc), nil
 	}
 
 	return "", nil
@@ -162,13 +202,229 @@ func hoverConstValue(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast
 
 	dstEnum := GetEnumNodeByEnumValue(dstAst.AST(), identifier)
 	if dstEnum != nil {
-		return format.MustFormatEnum(dstEnum), nil
+		return HoverContent(FormatDefinition(dstEnum), dstEnum.Doc()), nil
 	}
 
 	dstConst := GetConstNode(dstAst.AST(), identifier)
 	if dstConst != nil {
-		return format.MustFormatConst(dstConst), nil
+		return HoverContent(FormatDefinition(dstConst), dstConst.Doc()), nil
 	}
 
 	return "", nil
 }
+
+// FormatDefinition formats definition node without its comments. It returns empty string for
+// other nodes
+func FormatDefinition(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Struct:
+		st := *n
+		st.Comments, st.EndLineComments = nil, nil
+		return format.MustFormatStruct(&st)
+	case *parser.Union:
+		union := *n
+		union.Comments, union.EndLineComments = nil, nil
+		return format.MustFormatUnion(&union)
+	case *parser.Exception:
+		exception := *n
+		exception.Comments, exception.EndLineComments = nil, nil
+		return format.MustFormatException(&exception)
+	case *parser.Enum:
+		enum := *n
+		enum.Comments, enum.EndLineComments = nil, nil
+		return format.MustFormatEnum(&enum)
+	case *parser.Typedef:
+		td := *n
+		td.Comments, td.EndLineComments = nil, nil
+		return format.MustFormatTypedef(&td)
+	case *parser.Const:
+		cst := *n
+		cst.Comments, cst.EndLineComments = nil, nil
+		return format.MustFormatConst(&cst)
+	case *parser.Service:
+		svc := *n
+		svc.Comments, svc.EndLineComments = nil, nil
+		return format.MustFormatService(&svc)
+	}
+
+	return ""
+}
+
+// HoverContent renders code in thrift code block, doc is appended after code block
+func HoverContent(code string, doc string) string {
+	buf := strings.Builder{}
+	buf.WriteString("```thrift\n")
+	buf.WriteString(strings.Trim(code, "\n"))
+	buf.WriteString("\n```")
+	if doc != "" {
+		buf.WriteString("\n\n")
+		buf.WriteString(doc)
+	}
+
+	return buf.String()
+}
+
+// hoverField shows field in one line with its owner, resolved typedef chain and doc
+func hoverField(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, owner parser.Node, field *parser.Field) (string, error) {
+	if field.BadNode || field.ChildrenBadNode() || field.Index == nil || field.FieldType == nil {
+		return "", nil
+	}
+	copied := *field
+	copied.Comments, copied.EndLineComments, copied.ListSeparatorKeyword = nil, nil, nil
+	code := format.MustFormatField(&copied, " ", "", true)
+
+	var docs []string
+	if ownerName := fieldOwnerName(owner); ownerName != "" {
+		docs = append(docs, fmt.Sprintf("Field of %s", ownerName))
+	}
+	chain, err := ResolveTypeChain(ctx, ss, file, ast, field.FieldType)
+	if err != nil {
+		return "", err
+	}
+	if len(chain.Typedefs) > 0 {
+		docs = append(docs, "`"+chain.String()+"`")
+	}
+	if doc := field.Doc(); doc != "" {
+		docs = append(docs, doc)
+	}
+
+	return HoverContent(code, strings.Join(docs, "\n\n")), nil
+}
+
+// fieldOwnerName returns description of definition or function owning field, like "struct `User`"
+func fieldOwnerName(owner parser.Node) string {
+	switch n := owner.(type) {
+	case *parser.Struct:
+		return fmt.Sprintf("struct `%s`", n.Identifier.Name.Text)
+	case *parser.Union:
+		return fmt.Sprintf("union `%s`", n.Name.Name.Text)
+	case *parser.Exception:
+		return fmt.Sprintf("exception `%s`", n.Name.Name.Text)
+	case *parser.Function:
+		return fmt.Sprintf("function `%s`", n.Name.Name.Text)
+	case *parser.Throws:
+		return "throws"
+	}
+	return ""
+}
+
+// hoverFunction shows full signature of function, its service and services it extends which
+// declare the same function
+func hoverFunction(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, owner parser.Node, fn *parser.Function) (string, error) {
+	svc, ok := owner.(*parser.Service)
+	if !ok || fn.Name == nil || fn.Name.Name == nil {
+		return "", nil
+	}
+	copied := *fn
+	copied.Comments, copied.EndLineComments, copied.ListSeparatorKeyword = nil, nil, nil
+	code := format.MustFormatFunction(&copied, "")
+
+	docs := []string{fmt.Sprintf("Function of service `%s`", svc.Name.Name.Text)}
+	inherited, err := inheritedFunctionServices(ctx, ss, file, ast, svc, fn.Name.Name.Text)
+	if err != nil {
+		return "", err
+	}
+	if len(inherited) > 0 {
+		docs = append(docs, fmt.Sprintf("Inherited from service `%s`", strings.Join(inherited, "`, `")))
+	}
+	if doc := fn.Doc(); doc != "" {
+		docs = append(docs, doc)
+	}
+
+	return HoverContent(code, strings.Join(docs, "\n\n")), nil
+}
+
+// inheritedFunctionServices returns services extended by svc directly or indirectly which declare
+// function named name. Services are named relative to file
+func inheritedFunctionServices(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, svc *parser.Service, name string) ([]string, error) {
+	var res []string
+	err := walkExtendedServices(ctx, ss, file, ast, svc, func(parentFile uri.URI, _ *parser.Document, parent *parser.Service) {
+		if serviceFunction(parent, name) == nil {
+			return
+		}
+		parentName := parent.Name.Name.Text
+		if parentFile != file {
+			parentName = lsputils.GetIncludeName(parentFile) + "." + parentName
+		}
+		res = append(res, parentName)
+	})
+
+	return res, err
+}
+
+// hoverEnumValue shows enum value with its numeric value, implicit values are computed by parser
+func hoverEnumValue(owner parser.Node, value *parser.EnumValue) string {
+	enum, ok := owner.(*parser.Enum)
+	if !ok || enum.Name == nil || enum.Name.Name == nil || value.Name == nil || value.Name.Name == nil {
+		return ""
+	}
+
+	return HoverContent(fmt.Sprintf("%s.%s = %d", enum.Name.Name.Text, value.Name.Name.Text, value.Value), value.Doc())
+}
+
+// hoverInclude shows resolved path of include and definitions declared in included file
+func hoverInclude(ctx context.Context, ss *cache.Snapshot, file uri.URI, inc *parser.Include) (string, error) {
+	if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+		return "", nil
+	}
+	includeURI := lsputils.IncludeURI(file, inc.Path.Value.Text)
+	code := format.MustFormatInclude(inc)
+
+	docs := []string{fmt.Sprintf("`%s`", includeURI.Filename())}
+	fh, err := ss.ReadFile(ctx, includeURI)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fh.Content(); err != nil {
+		docs = append(docs, "File not found")
+		return HoverContent(code, strings.Join(docs, "\n\n")), nil
+	}
+
+	pf, err := ss.Parse(ctx, includeURI)
+	if err != nil {
+		return "", err
+	}
+	if pf.AST() != nil {
+		kinds := []string{"struct", "union", "exception", "enum", "typedef", "const", "service"}
+		names := make(map[string][]string)
+		for _, node := range pf.AST().Nodes {
+			if node.IsBadNode() {
+				continue
+			}
+			kind, name := definitionKindName(node)
+			if name != "" {
+				names[kind] = append(names[kind], "`"+name+"`")
+			}
+		}
+		for _, kind := range kinds {
+			if len(names[kind]) > 0 {
+				docs = append(docs, fmt.Sprintf("%s: %s", kind, strings.Join(names[kind], ", ")))
+			}
+		}
+	}
+
+	return HoverContent(code, strings.Join(docs, "\n\n")), nil
+}
+
+// definitionKindName returns kind and name of top level definition
+func definitionKindName(node parser.Node) (string, string) {
+	kind, id := DefinitionIdentifier(node)
+	if id == nil || id.Name == nil {
+		return "", ""
+	}
+	return kind, id.Name.Text
+}
+
+// hoverNamespace shows namespace and the generator it applies to
+func hoverNamespace(ns *parser.Namespace) string {
+	if ns.BadNode || ns.Language == nil || ns.Language.Name == nil || ns.Name == nil || ns.Name.Name == nil {
+		return ""
+	}
+	code := format.MustFormatNamespace(ns)
+
+	scope := ns.Language.Name.Text
+	if scope == "*" {
+		return HoverContent(code, fmt.Sprintf("Namespace `%s` of code generated by all generators", ns.Name.Name.Text))
+	}
+	return HoverContent(code, fmt.Sprintf("Namespace `%s` of code generated by `%s` generator", ns.Name.Name.Text, scope))
+}
@@ -0,0 +1,233 @@
+package codejump
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func TestHover(t *testing.T) {
+	file1 := `/**
+ * Test is a test struct
+ */
+struct Test {
+  1: required string name,
+}
+
+// error of api
+exception Error1 {
+  1: required string message,
+}
+
+enum Test3 {
+  ONE = 1,
+  TWO
+}
+
+typedef i64 ID
+typedef ID UserID`
+
+	file2 := `include "user.thrift"
+service Demo {
+  user.Test Api(1:user.Test3 arg1=user.Test3.TWO) throws (1:user.Error1 err)
+}
+typedef user.UserID Uid
+struct Req {
+  /** id of user */
+  1: optional user.UserID uid = 1
+}
+service BaseDemo {
+  void Ping()
+}
+service ExtDemo extends BaseDemo {
+  // ping server
+  void Ping()
+}
+`
+
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(file1),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/api.thrift",
+			Version: 0,
+			Content: []byte(file2),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/ns.thrift",
+			Version: 0,
+			Content: []byte("namespace go api"),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type args struct {
+		ctx  context.Context
+		ss   *cache.Snapshot
+		file uri.URI
+		pos  protocol.Position
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "struct with doc",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      2,
+					Character: 8,
+				},
+			},
+			want:      "```thrift\nstruct Test {\n    1: required string name,\n}\n```\n\nTest is a test struct",
+			assertion: assert.NoError,
+		},
+		{
+			name: "exception with doc",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      2,
+					Character: 65,
+				},
+			},
+			want:      "```thrift\nexception Error1 {\n    1: required string message,\n}\n```\n\nerror of api",
+			assertion: assert.NoError,
+		},
+		{
+			name: "enum without doc",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      2,
+					Character: 22,
+				},
+			},
+			want:      "```thrift\nenum Test3 {\n    ONE = 1,\n    TWO\n}\n```",
+			assertion: assert.NoError,
+		},
+		{
+			name: "typedef chain",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      4,
+					Character: 14,
+				},
+			},
+			want:      "```thrift\ntypedef ID UserID\n```\n\n`UserID -> ID -> i64`",
+			assertion: assert.NoError,
+		},
+		{
+			name: "field name",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      7,
+					Character: 28,
+				},
+			},
+			want:      "```thrift\n1: optional user.UserID uid = 1\n```\n\nField of struct `Req`\n\n`UserID -> ID -> i64`\n\nid of user",
+			assertion: assert.NoError,
+		},
+		{
+			name: "function name",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      2,
+					Character: 13,
+				},
+			},
+			want:      "```thrift\nuser.Test Api(1: user.Test3 arg1 = user.Test3.TWO) throws (1: user.Error1 err)\n```\n\nFunction of service `Demo`",
+			assertion: assert.NoError,
+		},
+		{
+			name: "inherited function",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      14,
+					Character: 8,
+				},
+			},
+			want:      "```thrift\nvoid Ping()\n```\n\nFunction of service `ExtDemo`\n\nInherited from service `BaseDemo`\n\nping server",
+			assertion: assert.NoError,
+		},
+		{
+			name: "include",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      0,
+					Character: 12,
+				},
+			},
+			want:      "```thrift\ninclude \"user.thrift\"\n```\n\n`/tmp/user.thrift`\n\nstruct: `Test`\n\nexception: `Error1`\n\nenum: `Test3`\n\ntypedef: `ID`, `UserID`",
+			assertion: assert.NoError,
+		},
+		{
+			name: "namespace",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/ns.thrift",
+				pos: protocol.Position{
+					Line:      0,
+					Character: 3,
+				},
+			},
+			want:      "```thrift\nnamespace go api\n```\n\nNamespace `api` of code generated by `go` generator",
+			assertion: assert.NoError,
+		},
+		{
+			name: "base type",
+			args: args{
+				ctx:  context.TODO(),
+				ss:   ss,
+				file: "file:///tmp/api.thrift",
+				pos: protocol.Position{
+					Line:      10,
+					Character: 3,
+				},
+			},
+			want:      "```thrift\nvoid\n```\n\n" + BuiltinDoc("void"),
+			assertion: assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Hover(tt.args.ctx, tt.args.ss, tt.args.file, tt.args.pos)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
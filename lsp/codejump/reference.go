@@ -63,6 +63,15 @@ func Reference(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protoc
 			}
 			// search in const value
 			return searchConstValueIdentifierReferences(ctx, ss, file, typeName)
+		} else if definitionType == "Field" {
+			// field -> struct, union, exception, function or throws
+			return searchFieldReferences(ctx, ss, file, nodePath[len(nodePath)-4], parentDefinitionNode.(*parser.Field))
+		} else if definitionType == "Function" {
+			svc, ok := nodePath[len(nodePath)-4].(*parser.Service)
+			if !ok {
+				return
+			}
+			return searchFunctionReferences(ctx, ss, file, pf.AST(), svc, parentDefinitionNode.(*parser.Function))
 		} else if definitionType == "Service" {
 			svcName := targetNode.(*parser.IdentifierName).Text
 			if !strings.Contains(svcName, ".") {
@@ -86,6 +95,14 @@ func Reference(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protoc
 		return searchIdentifierReferences(ctx, ss, file, typeName, definitionType)
 	case "ConstValue":
 		return searchConstValueReferences(ctx, ss, file, pf.AST(), nodePath, targetNode)
+	case "LiteralValue":
+		// literalValue -> literal -> include
+		if len(nodePath) < 3 {
+			return
+		}
+		if inc, ok := nodePath[len(nodePath)-3].(*parser.Include); ok {
+			return searchIncludeReferences(file, pf.AST(), inc), nil
+		}
 	default:
 		log.Warningln("unsupport type for reference:", targetNode.Type())
 	}
@@ -469,3 +486,390 @@ func searchConstValueIdentifierReference(ctx context.Context, ss *cache.Snapshot
 
 	return
 }
+
+// searchFieldReferences searches field names used as keys of struct literals in const values.
+// owner is struct, union or exception declaring field in file
+func searchFieldReferences(ctx context.Context, ss *cache.Snapshot, file uri.URI, owner parser.Node, field *parser.Field) (res []protocol.Location, err error) {
+	res = make([]protocol.Location, 0)
+	kind, ownerName := definitionKindName(owner)
+	if (kind != "struct" && kind != "union" && kind != "exception") ||
+		field.Identifier == nil || field.Identifier.Name == nil {
+		return
+	}
+	fieldName := field.Identifier.Name.Text
+
+	var errs []error
+	for _, referenceFile := range ss.Graph().IncludedBy(file) {
+		pf, err := ss.Parse(ctx, referenceFile)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if pf.AST() == nil {
+			continue
+		}
+
+		walkConstValues(pf.AST(), func(ft *parser.FieldType, cv *parser.ConstValue) {
+			locations, err := searchFieldKeys(ctx, ss, referenceFile, referenceFile, pf.AST(), ft, cv, file, ownerName, fieldName)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			res = append(res, locations...)
+		})
+	}
+
+	if len(errs) > 0 {
+		err = utilerrors.NewAggregate(errs)
+	}
+
+	return
+}
+
+// searchFieldKeys searches keys named fieldName in const value cv of type ft. cv is written in
+// valueFile and ft is written in file. Keys are searched only in struct literals of definition
+// ownerName declared in ownerFile
+func searchFieldKeys(ctx context.Context, ss *cache.Snapshot, valueFile uri.URI, file uri.URI, ast *parser.Document, ft *parser.FieldType,
+	cv *parser.ConstValue, ownerFile uri.URI, ownerName string, fieldName string) (res []protocol.Location, err error) {
+	if ft == nil || cv == nil || cv.BadNode {
+		return
+	}
+	resolved, err := ResolveFieldType(ctx, ss, file, ast, ft)
+	if err != nil || resolved == nil {
+		return
+	}
+
+	values, _ := cv.Value.([]*parser.ConstValue)
+	if resolved.Definition == nil {
+		// container types
+		for _, item := range values {
+			if item == nil || item.BadNode {
+				continue
+			}
+			var locations []protocol.Location
+			if item.TypeName == "pair" {
+				key, _ := item.Key.(*parser.ConstValue)
+				value, _ := item.Value.(*parser.ConstValue)
+				locations, err = searchFieldKeys(ctx, ss, valueFile, resolved.File, resolved.AST, resolved.FieldType.KeyType, key, ownerFile, ownerName, fieldName)
+				if err != nil {
+					return
+				}
+				res = append(res, locations...)
+				locations, err = searchFieldKeys(ctx, ss, valueFile, resolved.File, resolved.AST, resolved.FieldType.ValueType, value, ownerFile, ownerName, fieldName)
+			} else {
+				// element type of list and set is recorded as KeyType
+				locations, err = searchFieldKeys(ctx, ss, valueFile, resolved.File, resolved.AST, resolved.FieldType.KeyType, item, ownerFile, ownerName, fieldName)
+			}
+			if err != nil {
+				return
+			}
+			res = append(res, locations...)
+		}
+		return
+	}
+
+	var fields []*parser.Field
+	switch n := resolved.Definition.(type) {
+	case *parser.Struct:
+		fields = n.Fields
+	case *parser.Union:
+		fields = n.Fields
+	case *parser.Exception:
+		fields = n.Fields
+	default:
+		return
+	}
+	if cv.TypeName != "map" {
+		return
+	}
+	_, name := definitionKindName(resolved.Definition)
+	isOwner := resolved.DefinitionFile == ownerFile && name == ownerName
+
+	for _, pair := range values {
+		if pair == nil || pair.BadNode || pair.TypeName != "pair" {
+			continue
+		}
+		key, _ := pair.Key.(*parser.ConstValue)
+		value, _ := pair.Value.(*parser.ConstValue)
+		if key == nil || key.BadNode || key.TypeName != "string" {
+			continue
+		}
+		literal, ok := key.Value.(*parser.Literal)
+		if !ok || literal.Value == nil {
+			continue
+		}
+		if isOwner && literal.Value.Text == fieldName {
+			res = append(res, jump(valueFile, literal.Value))
+		}
+
+		for _, field := range fields {
+			if field.BadNode || field.Identifier == nil || field.Identifier.Name == nil || field.Identifier.Name.Text != literal.Value.Text {
+				continue
+			}
+			locations, err := searchFieldKeys(ctx, ss, valueFile, resolved.DefinitionFile, resolved.DefinitionAST, field.FieldType, value, ownerFile, ownerName, fieldName)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, locations...)
+			break
+		}
+	}
+
+	return
+}
+
+// walkConstValues calls fn with values of consts and default values of fields, along with their types
+func walkConstValues(ast *parser.Document, fn func(ft *parser.FieldType, cv *parser.ConstValue)) {
+	walkFields := func(fields []*parser.Field) {
+		for _, field := range fields {
+			if field.BadNode || field.FieldType == nil || field.ConstValue == nil {
+				continue
+			}
+			fn(field.FieldType, field.ConstValue)
+		}
+	}
+
+	for _, cst := range ast.Consts {
+		if cst.BadNode || cst.ConstType == nil || cst.Value == nil {
+			continue
+		}
+		fn(cst.ConstType, cst.Value)
+	}
+	for _, st := range ast.Structs {
+		walkFields(st.Fields)
+	}
+	for _, union := range ast.Unions {
+		walkFields(union.Fields)
+	}
+	for _, excep := range ast.Exceptions {
+		walkFields(excep.Fields)
+	}
+	for _, svc := range ast.Services {
+		for _, function := range svc.Functions {
+			walkFields(function.Arguments)
+		}
+	}
+}
+
+// searchFunctionReferences searches functions with the same name in services extending the service
+// which declares function first. Function itself is excluded
+func searchFunctionReferences(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, svc *parser.Service, fn *parser.Function) (res []protocol.Location, err error) {
+	res = make([]protocol.Location, 0)
+	if fn.Name == nil || fn.Name.Name == nil {
+		return
+	}
+	_, family, err := serviceFamily(ctx, ss, file, ast, svc, fn.Name.Name.Text)
+	for _, s := range family {
+		if function := serviceFunction(s.Service, fn.Name.Name.Text); function != nil && function != fn {
+			res = append(res, jump(s.File, function.Name.Name))
+		}
+	}
+
+	return
+}
+
+// serviceLocation is a service and the file declaring it
+type serviceLocation struct {
+	File    uri.URI
+	AST     *parser.Document
+	Service *parser.Service
+}
+
+// serviceFamily finds the top most service declaring function name among svc and services it extends.
+// family is that service and services extending it directly or indirectly, ancestors are services
+// it extends
+func serviceFamily(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, svc *parser.Service, name string) (ancestors []*serviceLocation, family []*serviceLocation, err error) {
+	if svc.Name == nil || svc.Name.Name == nil {
+		return
+	}
+
+	// parents are walked from the nearest one
+	var parents []*serviceLocation
+	err = walkExtendedServices(ctx, ss, file, ast, svc, func(parentFile uri.URI, parentAST *parser.Document, parent *parser.Service) {
+		parents = append(parents, &serviceLocation{File: parentFile, AST: parentAST, Service: parent})
+	})
+	if err != nil {
+		return
+	}
+	root := &serviceLocation{File: file, AST: ast, Service: svc}
+	ancestors = parents
+	for i, parent := range parents {
+		if serviceFunction(parent.Service, name) != nil {
+			root, ancestors = parent, parents[i+1:]
+		}
+	}
+	rootName := root.Service.Name.Name.Text
+
+	var errs []error
+	for _, referenceFile := range ss.Graph().IncludedBy(root.File) {
+		pf, err := ss.Parse(ctx, referenceFile)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if pf.AST() == nil {
+			continue
+		}
+
+		for _, s := range pf.AST().Services {
+			if s.BadNode || s.Name == nil || s.Name.Name == nil {
+				continue
+			}
+
+			matched := referenceFile == root.File && s.Name.Name.Text == rootName
+			err := walkExtendedServices(ctx, ss, referenceFile, pf.AST(), s, func(parentFile uri.URI, _ *parser.Document, parent *parser.Service) {
+				if parentFile == root.File && parent.Name.Name.Text == rootName {
+					matched = true
+				}
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if matched {
+				family = append(family, &serviceLocation{File: referenceFile, AST: pf.AST(), Service: s})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		err = utilerrors.NewAggregate(errs)
+	}
+
+	return
+}
+
+// walkExtendedServices calls fn with services extended by svc directly or indirectly, from the
+// nearest one. Cyclic extends are walked once
+func walkExtendedServices(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, svc *parser.Service, fn func(file uri.URI, ast *parser.Document, svc *parser.Service)) error {
+	visited := map[*parser.Service]struct{}{svc: {}}
+	for svc.Extends != nil && svc.Extends.Name != nil {
+		dstFile, dstAst, parent, _, err := ResolveService(ctx, ss, file, ast, svc.Extends.Name.Text)
+		if err != nil {
+			return err
+		}
+		if parent == nil || parent.Name == nil || parent.Name.Name == nil {
+			return nil
+		}
+		if _, ok := visited[parent]; ok {
+			return nil
+		}
+		visited[parent] = struct{}{}
+
+		fn(dstFile, dstAst, parent)
+		svc, file, ast = parent, dstFile, dstAst
+	}
+
+	return nil
+}
+
+// serviceFunction returns function of svc by name
+func serviceFunction(svc *parser.Service, name string) *parser.Function {
+	for _, fn := range svc.Functions {
+		if !fn.BadNode && fn.Name != nil && fn.Name.Name != nil && fn.Name.Name.Text == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// searchIncludeReferences searches usages qualified by include name of inc, like `base.User`,
+// `base.Status.OK` and `extends base.BaseService`. Locations cover the include name only
+func searchIncludeReferences(file uri.URI, ast *parser.Document, inc *parser.Include) (res []protocol.Location) {
+	res = make([]protocol.Location, 0)
+	if inc.BadNode || inc.Path == nil || inc.Path.Value == nil {
+		return
+	}
+	prefix := inc.Name()
+
+	add := func(node parser.Node, name string) {
+		if include, _ := lsputils.ParseIdent(file, ast.Includes, name); include != prefix || lsputils.IsLocalEnumValue(ast, name) {
+			return
+		}
+		loc := jump(file, node)
+		loc.Range.End = protocol.Position{Line: loc.Range.Start.Line, Character: loc.Range.Start.Character + uint32(len(prefix))}
+		res = append(res, loc)
+	}
+
+	var searchFieldType func(ft *parser.FieldType)
+	searchFieldType = func(ft *parser.FieldType) {
+		if ft == nil || ft.BadNode {
+			return
+		}
+		if ft.TypeName != nil {
+			add(ft.TypeName, ft.TypeName.Name)
+		}
+		searchFieldType(ft.KeyType)
+		searchFieldType(ft.ValueType)
+	}
+	var searchConstValue func(cv *parser.ConstValue)
+	searchConstValue = func(cv *parser.ConstValue) {
+		if cv == nil || cv.BadNode {
+			return
+		}
+		switch cv.TypeName {
+		case "identifier":
+			name, _ := cv.Value.(string)
+			add(cv, name)
+		case "pair":
+			key, _ := cv.Key.(*parser.ConstValue)
+			value, _ := cv.Value.(*parser.ConstValue)
+			searchConstValue(key)
+			searchConstValue(value)
+		case "list", "map":
+			values, _ := cv.Value.([]*parser.ConstValue)
+			for _, item := range values {
+				searchConstValue(item)
+			}
+		}
+	}
+	searchFields := func(fields []*parser.Field) {
+		for _, field := range fields {
+			if field.BadNode {
+				continue
+			}
+			searchFieldType(field.FieldType)
+			searchConstValue(field.ConstValue)
+		}
+	}
+
+	// definitions are walked in declaration order
+	for _, node := range ast.Nodes {
+		if node.IsBadNode() {
+			continue
+		}
+		switch n := node.(type) {
+		case *parser.Struct:
+			searchFields(n.Fields)
+		case *parser.Union:
+			searchFields(n.Fields)
+		case *parser.Exception:
+			searchFields(n.Fields)
+		case *parser.Typedef:
+			searchFieldType(n.T)
+		case *parser.Const:
+			searchFieldType(n.ConstType)
+			searchConstValue(n.Value)
+		case *parser.Enum:
+			for _, v := range n.Values {
+				if !v.BadNode {
+					searchConstValue(v.ValueNode)
+				}
+			}
+		case *parser.Service:
+			if n.Extends != nil && n.Extends.Name != nil {
+				add(n.Extends.Name, n.Extends.Name.Text)
+			}
+			for _, function := range n.Functions {
+				if function.BadNode {
+					continue
+				}
+				searchFieldType(function.FunctionType)
+				searchFields(function.Arguments)
+				if function.Throws != nil {
+					searchFields(function.Throws.Fields)
+				}
+			}
+		}
+	}
+
+	return
+}
@@ -689,3 +689,97 @@ const UserKind kind = "1"
 		})
 	}
 }
+
+func TestReference_Member(t *testing.T) {
+	base := `struct Info {
+  1: string city
+}
+struct User {
+  1: string name
+  2: Info info
+}
+service BaseService {
+  void ping()
+}`
+
+	api := `include "base.thrift"
+const base.User user = {"name": "a", "info": {"city": "x"}}
+const list<base.Info> infos = [{"city": "y"}]
+service Api extends base.BaseService {
+  void ping()
+}
+service Ext extends Api {
+  void ping()
+}
+enum base {
+  X
+}
+const base local = base.X`
+
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{URI: "file:///tmp/base.thrift", Content: []byte(base), From: cache.FileChangeTypeDidOpen},
+		{URI: "file:///tmp/api.thrift", Content: []byte(api), From: cache.FileChangeTypeDidOpen},
+	})
+
+	location := func(file uri.URI, line, start, end uint32) protocol.Location {
+		return protocol.Location{
+			URI: file,
+			Range: protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		file uri.URI
+		pos  protocol.Position
+		want []protocol.Location
+	}{
+		{
+			name: "field in nested struct literal",
+			file: "file:///tmp/base.thrift",
+			pos:  protocol.Position{Line: 1, Character: 13},
+			want: []protocol.Location{
+				location("file:///tmp/api.thrift", 1, 47, 51),
+				location("file:///tmp/api.thrift", 2, 33, 37),
+			},
+		},
+		{
+			name: "field in struct literal",
+			file: "file:///tmp/base.thrift",
+			pos:  protocol.Position{Line: 4, Character: 13},
+			want: []protocol.Location{
+				location("file:///tmp/api.thrift", 1, 25, 29),
+			},
+		},
+		{
+			name: "overridden function",
+			file: "file:///tmp/api.thrift",
+			pos:  protocol.Position{Line: 7, Character: 8},
+			want: []protocol.Location{
+				location("file:///tmp/base.thrift", 8, 7, 11),
+				location("file:///tmp/api.thrift", 4, 7, 11),
+			},
+		},
+		{
+			// value of local enum base isn't qualified by include name
+			name: "include prefix",
+			file: "file:///tmp/api.thrift",
+			pos:  protocol.Position{Line: 0, Character: 10},
+			want: []protocol.Location{
+				location("file:///tmp/api.thrift", 1, 6, 10),
+				location("file:///tmp/api.thrift", 2, 11, 15),
+				location("file:///tmp/api.thrift", 3, 20, 24),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reference(context.TODO(), ss, tt.file, tt.pos)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/joyme123/protocol"
@@ -62,6 +63,10 @@ func Rename(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protocol.
 
 	self := lsputils.ASTNodeToRange(targetNode)
 
+	if err = validateNewName(newName); err != nil {
+		return
+	}
+
 	switch targetNode.Type() {
 	case "IdentifierName":
 		if len(nodePath) <= 2 {
@@ -70,14 +75,50 @@ func Rename(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protocol.
 		// identifierName -> identifier -> definition
 		parentDefinitionNode := nodePath[len(nodePath)-3]
 		definitionType := parentDefinitionNode.Type()
-		if definitionType == "EnumValue" || definitionType == "Const" {
-			var typeName string
-			if definitionType == "Const" {
-				typeName = fmt.Sprintf("%s.%s", lsputils.GetIncludeName(file), targetNode.(*parser.IdentifierName).Text)
-			} else {
-				enumNode := nodePath[len(nodePath)-4]
-				typeName = fmt.Sprintf("%s.%s.%s", lsputils.GetIncludeName(file), enumNode.(*parser.Enum).Name.Name.Text, targetNode.(*parser.IdentifierName).Text)
+		if definitionType == "Field" {
+			return renameField(ctx, ss, file, nodePath[len(nodePath)-4], parentDefinitionNode.(*parser.Field), newName)
+		} else if definitionType == "Function" {
+			svc, ok := nodePath[len(nodePath)-4].(*parser.Service)
+			if !ok {
+				return nil, nil
+			}
+			return renameFunction(ctx, ss, file, pf.AST(), svc, parentDefinitionNode.(*parser.Function), newName)
+		}
+
+		if definitionType == "EnumValue" {
+			enumNode := nodePath[len(nodePath)-4].(*parser.Enum)
+			if err := enumValueConflict(enumNode, nodePath[len(nodePath)-3].(*parser.EnumValue).Name, newName); err != nil {
+				return nil, err
 			}
+			typeName := fmt.Sprintf("%s.%s.%s", lsputils.GetIncludeName(file), enumNode.Name.Name.Text, targetNode.(*parser.IdentifierName).Text)
+			// search in const value
+			locations, err := searchConstValueIdentifierReferences(ctx, ss, file, typeName)
+			if err != nil {
+				return nil, err
+			}
+
+			// references of enum value are qualified by enum name
+			res := convertLocationToWorkspaceEdit(locations, file, enumNode.Name.Name.Text+"."+newName)
+			res.Changes[file] = append(res.Changes[file], protocol.TextEdit{Range: self, NewText: newName})
+			return res, nil
+		}
+
+		if svc, ok := parentDefinitionNode.(*parser.Service); ok && svc.Extends != nil && svc.Extends.Name == targetNode {
+			// extends refers to service declared in other file maybe
+			dstFile, _, parent, _, err := ResolveService(ctx, ss, file, pf.AST(), svc.Extends.Name.Text)
+			if err != nil {
+				return nil, err
+			}
+			if parent != nil {
+				if err := definitionConflict(ctx, ss, dstFile, parent, newName); err != nil {
+					return nil, err
+				}
+			}
+		} else if err := definitionConflict(ctx, ss, file, parentDefinitionNode, newName); err != nil {
+			return nil, err
+		}
+		if definitionType == "Const" {
+			typeName := fmt.Sprintf("%s.%s", lsputils.GetIncludeName(file), targetNode.(*parser.IdentifierName).Text)
 			// search in const value
 			locations, err := searchConstValueIdentifierReferences(ctx, ss, file, typeName)
 			if err != nil {
@@ -131,16 +172,7 @@ func Rename(ctx context.Context, ss *cache.Snapshot, file uri.URI, pos protocol.
 		})
 		return convertLocationToWorkspaceEdit(locations, file, newName), nil
 	case "ConstValue":
-		locations, err := searchConstValueReferences(ctx, ss, file, pf.AST(), nodePath, targetNode)
-		if err != nil {
-			return nil, err
-		}
-
-		locations = append(locations, protocol.Location{
-			URI:   file,
-			Range: self,
-		})
-		return convertLocationToWorkspaceEdit(locations, file, newName), nil
+		return renameConstValue(ctx, ss, file, pf.AST(), targetNode, newName)
 	default:
 		err = fmt.Errorf("%s doesn't support rename", targetNode.Type())
 		return
@@ -165,3 +197,189 @@ func convertLocationToWorkspaceEdit(locations []protocol.Location, fileURI uri.U
 
 	return res
 }
+
+// renameConstValue renames const or enum value referred by const value. Definition and all
+// references are renamed
+func renameConstValue(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, targetNode parser.Node, newName string) (*protocol.WorkspaceEdit, error) {
+	definitionFile, identifier, err := ConstValueTypeDefinitionIdentifier(ctx, ss, file, ast, targetNode)
+	if err != nil || identifier == nil || identifier.Name == nil {
+		return nil, err
+	}
+	pf, err := ss.Parse(ctx, definitionFile)
+	if err != nil {
+		return nil, err
+	}
+	if pf.AST() == nil {
+		return nil, nil
+	}
+
+	// references of enum value are qualified by enum name
+	name, qualifiedNewName := identifier.Name.Text, newName
+	if enum := enumOfValue(pf.AST(), identifier); enum != nil {
+		if err := enumValueConflict(enum, identifier, newName); err != nil {
+			return nil, err
+		}
+		name = enum.Name.Name.Text + "." + name
+		qualifiedNewName = enum.Name.Name.Text + "." + newName
+	} else if err := definitionConflict(ctx, ss, definitionFile, GetConstNode(pf.AST(), name), newName); err != nil {
+		return nil, err
+	}
+
+	locations, err := searchConstValueIdentifierReferences(ctx, ss, definitionFile, lsputils.GetIncludeName(definitionFile)+"."+name)
+	if err != nil {
+		return nil, err
+	}
+	res := convertLocationToWorkspaceEdit(locations, definitionFile, qualifiedNewName)
+	res.Changes[definitionFile] = append(res.Changes[definitionFile], protocol.TextEdit{
+		Range:   lsputils.ASTNodeToRange(identifier.Name),
+		NewText: newName,
+	})
+
+	return res, nil
+}
+
+// enumOfValue returns enum declaring value with name identifier
+func enumOfValue(ast *parser.Document, identifier *parser.Identifier) *parser.Enum {
+	for _, enum := range ast.Enums {
+		for _, v := range enum.Values {
+			if v.Name == identifier {
+				return enum
+			}
+		}
+	}
+	return nil
+}
+
+// renameField renames field and its usages as keys of struct literals. owner is struct, union,
+// exception, function or throws declaring field
+func renameField(ctx context.Context, ss *cache.Snapshot, file uri.URI, owner parser.Node, field *parser.Field, newName string) (*protocol.WorkspaceEdit, error) {
+	if field.Identifier == nil || field.Identifier.Name == nil {
+		return nil, nil
+	}
+
+	var fields []*parser.Field
+	switch n := owner.(type) {
+	case *parser.Struct:
+		fields = n.Fields
+	case *parser.Union:
+		fields = n.Fields
+	case *parser.Exception:
+		fields = n.Fields
+	case *parser.Function:
+		fields = n.Arguments
+	case *parser.Throws:
+		fields = n.Fields
+	}
+	for _, f := range fields {
+		if f != field && !f.BadNode && f.Identifier != nil && f.Identifier.Name != nil && f.Identifier.Name.Text == newName {
+			return nil, fmt.Errorf("field %s is already declared", newName)
+		}
+	}
+
+	locations, err := searchFieldReferences(ctx, ss, file, owner, field)
+	if err != nil {
+		return nil, err
+	}
+	locations = append(locations, jump(file, field.Identifier.Name))
+
+	return convertLocationToTextEdits(locations, newName), nil
+}
+
+// renameFunction renames function together with functions overriding it or overridden by it
+func renameFunction(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, svc *parser.Service, fn *parser.Function, newName string) (*protocol.WorkspaceEdit, error) {
+	if fn.Name == nil || fn.Name.Name == nil {
+		return nil, nil
+	}
+
+	ancestors, family, err := serviceFamily(ctx, ss, file, ast, svc, fn.Name.Name.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []protocol.Location
+	for _, s := range append(ancestors, family...) {
+		if newName != fn.Name.Name.Text && serviceFunction(s.Service, newName) != nil {
+			return nil, fmt.Errorf("function %s is already declared in service %s", newName, s.Service.Name.Name.Text)
+		}
+	}
+	for _, s := range family {
+		if function := serviceFunction(s.Service, fn.Name.Name.Text); function != nil {
+			locations = append(locations, jump(s.File, function.Name.Name))
+		}
+	}
+
+	return convertLocationToTextEdits(locations, newName), nil
+}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// thriftKeywords can't be used as identifier
+var thriftKeywords = map[string]struct{}{
+	"include": {}, "cpp_include": {}, "namespace": {}, "const": {}, "typedef": {}, "enum": {},
+	"senum": {}, "struct": {}, "union": {}, "exception": {}, "extends": {}, "service": {},
+	"required": {}, "optional": {}, "oneway": {}, "void": {}, "throws": {}, "bool": {},
+	"byte": {}, "i8": {}, "i16": {}, "i32": {}, "i64": {}, "double": {}, "string": {},
+	"binary": {}, "uuid": {}, "slist": {}, "map": {}, "list": {}, "set": {}, "true": {},
+	"false": {},
+}
+
+// validateNewName checks new name is a valid identifier
+func validateNewName(newName string) error {
+	if !identifierRegexp.MatchString(newName) {
+		return fmt.Errorf("%q isn't a valid identifier", newName)
+	}
+	if _, ok := thriftKeywords[newName]; ok {
+		return fmt.Errorf("%q is a keyword of thrift", newName)
+	}
+	return nil
+}
+
+// definitionConflict checks whether name is declared by a definition other than self in file or
+// any file including it
+func definitionConflict(ctx context.Context, ss *cache.Snapshot, file uri.URI, self parser.Node, name string) error {
+	for _, f := range ss.Graph().IncludedBy(file) {
+		pf, err := ss.Parse(ctx, f)
+		if err != nil {
+			return err
+		}
+		if pf.AST() == nil {
+			continue
+		}
+		for _, node := range pf.AST().Nodes {
+			if node.IsBadNode() || node == self {
+				continue
+			}
+			if kind, defName := definitionKindName(node); defName == name {
+				return fmt.Errorf("%s %s is already declared in %s", kind, name, lsputils.GetIncludeName(f))
+			}
+		}
+	}
+	return nil
+}
+
+// enumValueConflict checks whether name is declared by a value of enum other than self
+func enumValueConflict(enum *parser.Enum, self *parser.Identifier, name string) error {
+	for _, v := range enum.Values {
+		if !v.BadNode && v.Name != self && v.Name != nil && v.Name.Name != nil && v.Name.Name.Text == name {
+			return fmt.Errorf("enum value %s.%s is already declared", enum.Name.Name.Text, name)
+		}
+	}
+	return nil
+}
+
+// convertLocationToTextEdits replaces every location with newName. It is used by names which aren't
+// qualified by include name, like fields and functions
+func convertLocationToTextEdits(locations []protocol.Location, newName string) *protocol.WorkspaceEdit {
+	res := &protocol.WorkspaceEdit{
+		Changes: make(map[protocol.DocumentURI][]protocol.TextEdit),
+	}
+
+	for _, loc := range locations {
+		res.Changes[loc.URI] = append(res.Changes[loc.URI], protocol.TextEdit{
+			Range:   loc.Range,
+			NewText: newName,
+		})
+	}
+
+	return res
+}
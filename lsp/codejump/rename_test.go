@@ -633,3 +633,153 @@ const UserKind kind = "1"
 		})
 	}
 }
+
+func TestRename_Member(t *testing.T) {
+	base := `struct Info {
+  1: string city
+}
+struct User {
+  1: string name
+  2: Info info
+}
+service BaseService {
+  void ping()
+  void stop()
+}
+enum Status {
+  OK
+  FAILED
+}`
+
+	api := `include "base.thrift"
+const base.User user = {"name": "a", "info": {"city": "x"}}
+service Api extends base.BaseService {
+  void ping()
+}
+service Ext extends Api {
+  void ping()
+}
+const base.Status s = base.Status.OK`
+
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{URI: "file:///tmp/base.thrift", Content: []byte(base), From: cache.FileChangeTypeDidOpen},
+		{URI: "file:///tmp/api.thrift", Content: []byte(api), From: cache.FileChangeTypeDidOpen},
+	})
+
+	edit := func(line, start, end uint32, newText string) protocol.TextEdit {
+		return protocol.TextEdit{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			},
+			NewText: newText,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		file      uri.URI
+		pos       protocol.Position
+		newName   string
+		want      *protocol.WorkspaceEdit
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "field",
+			file:    "file:///tmp/base.thrift",
+			pos:     protocol.Position{Line: 1, Character: 13},
+			newName: "town",
+			want: &protocol.WorkspaceEdit{
+				Changes: map[uri.URI][]protocol.TextEdit{
+					"file:///tmp/base.thrift": {edit(1, 12, 16, "town")},
+					"file:///tmp/api.thrift":  {edit(1, 47, 51, "town")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:    "function with overrides",
+			file:    "file:///tmp/api.thrift",
+			pos:     protocol.Position{Line: 6, Character: 8},
+			newName: "health",
+			want: &protocol.WorkspaceEdit{
+				Changes: map[uri.URI][]protocol.TextEdit{
+					"file:///tmp/base.thrift": {edit(8, 7, 11, "health")},
+					"file:///tmp/api.thrift":  {edit(3, 7, 11, "health"), edit(6, 7, 11, "health")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:    "enum value reference",
+			file:    "file:///tmp/api.thrift",
+			pos:     protocol.Position{Line: 8, Character: 30},
+			newName: "DONE",
+			want: &protocol.WorkspaceEdit{
+				Changes: map[uri.URI][]protocol.TextEdit{
+					"file:///tmp/base.thrift": {edit(12, 2, 4, "DONE")},
+					"file:///tmp/api.thrift":  {edit(8, 22, 36, "base.Status.DONE")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "invalid identifier",
+			file:      "file:///tmp/base.thrift",
+			pos:       protocol.Position{Line: 1, Character: 13},
+			newName:   "1city",
+			assertion: assert.Error,
+		},
+		{
+			name:      "keyword",
+			file:      "file:///tmp/base.thrift",
+			pos:       protocol.Position{Line: 1, Character: 13},
+			newName:   "struct",
+			assertion: assert.Error,
+		},
+		{
+			name:      "field conflict",
+			file:      "file:///tmp/base.thrift",
+			pos:       protocol.Position{Line: 4, Character: 13},
+			newName:   "info",
+			assertion: assert.Error,
+		},
+		{
+			name:      "function conflict in extended service",
+			file:      "file:///tmp/api.thrift",
+			pos:       protocol.Position{Line: 6, Character: 8},
+			newName:   "stop",
+			assertion: assert.Error,
+		},
+		{
+			name:      "definition conflict",
+			file:      "file:///tmp/base.thrift",
+			pos:       protocol.Position{Line: 0, Character: 8},
+			newName:   "User",
+			assertion: assert.Error,
+		},
+		{
+			name:    "definition conflict in includer",
+			file:    "file:///tmp/base.thrift",
+			pos:     protocol.Position{Line: 0, Character: 8},
+			newName: "Api",
+			assertion: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.EqualError(t, err, "service Api is already declared in api")
+			},
+		},
+		{
+			name:      "enum value conflict",
+			file:      "file:///tmp/api.thrift",
+			pos:       protocol.Position{Line: 8, Character: 30},
+			newName:   "FAILED",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Rename(context.TODO(), ss, tt.file, tt.pos, tt.newName)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package codejump
+
+import (
+	"context"
+	"strings"
+
+	"github.com/joyme123/thrift-ls/format"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// maxTypedefDepth limits typedef resolving, it avoids infinite loop of cyclic typedefs
+const maxTypedefDepth = 32
+
+// ResolvedType is the underlying type of a field type, typedefs are resolved.
+type ResolvedType struct {
+	// File is the file where FieldType is written. type names in FieldType should be looked up from it
+	File uri.URI
+	AST  *parser.Document
+	// FieldType is a base type, container type or reference to Definition
+	FieldType *parser.FieldType
+	// Definition is struct, union, exception or enum referred by FieldType.
+	// It is nil for base types and container types
+	Definition parser.Node
+	// DefinitionFile is the file where Definition is declared
+	DefinitionFile uri.URI
+	DefinitionAST  *parser.Document
+}
+
+// ResolveFieldType resolves typedefs of ft through includes. It returns nil if type is undefined
+// or typedefs are cyclic
+func ResolveFieldType(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, ft *parser.FieldType) (*ResolvedType, error) {
+	chain, err := ResolveTypeChain(ctx, ss, file, ast, ft)
+	if err != nil || chain == nil {
+		return nil, err
+	}
+
+	return chain.Resolved, nil
+}
+
+// TypedefLink is a typedef passed by when resolving a field type
+type TypedefLink struct {
+	// File is the file where Typedef is declared
+	File    uri.URI
+	AST     *parser.Document
+	Typedef *parser.Typedef
+}
+
+// TypeChain is the full path of resolving a field type, like `A -> B -> list<C>`
+type TypeChain struct {
+	// Typedefs are typedefs in resolving order. If chain is cyclic, the typedef closing
+	// the cycle is appended again as the last one
+	Typedefs []*TypedefLink
+	// Target is the last field type in chain. It is the undefined type if Resolved is nil
+	// and chain isn't cyclic
+	Target *parser.FieldType
+	// Resolved is the underlying type. It is nil if type is undefined or typedefs are cyclic
+	Resolved *ResolvedType
+	Cyclic   bool
+}
+
+// ResolveTypeChain resolves typedefs of ft through includes and records every typedef on the way
+func ResolveTypeChain(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, ft *parser.FieldType) (*TypeChain, error) {
+	chain := &TypeChain{}
+	err := chain.resolve(ctx, ss, file, ast, ft)
+	return chain, err
+}
+
+// ResolveTypedefChain resolves typedef td declared in file. td is the first typedef in chain
+func ResolveTypedefChain(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, td *parser.Typedef) (*TypeChain, error) {
+	chain := &TypeChain{
+		Typedefs: []*TypedefLink{{File: file, AST: ast, Typedef: td}},
+	}
+	err := chain.resolve(ctx, ss, file, ast, td.T)
+	return chain, err
+}
+
+func (c *TypeChain) resolve(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, ft *parser.FieldType) error {
+	for depth := 0; depth < maxTypedefDepth; depth++ {
+		c.Target = ft
+		if ast == nil || ft == nil || ft.BadNode || ft.TypeName == nil {
+			return nil
+		}
+
+		if IsBasicType(ft.TypeName.Name) {
+			c.Resolved = &ResolvedType{
+				File:      file,
+				AST:       ast,
+				FieldType: ft,
+			}
+			return nil
+		}
+
+		dstFile, dstAst, node, err := LookupTypeName(ctx, ss, file, ast, ft.TypeName.Name)
+		if err != nil || node == nil {
+			return err
+		}
+
+		td, ok := node.(*parser.Typedef)
+		if !ok {
+			c.Resolved = &ResolvedType{
+				File:           file,
+				AST:            ast,
+				FieldType:      ft,
+				Definition:     node,
+				DefinitionFile: dstFile,
+				DefinitionAST:  dstAst,
+			}
+			return nil
+		}
+
+		visited := c.contains(td)
+		c.Typedefs = append(c.Typedefs, &TypedefLink{File: dstFile, AST: dstAst, Typedef: td})
+		if visited {
+			c.Cyclic = true
+			return nil
+		}
+
+		file, ast, ft = dstFile, dstAst, td.T
+	}
+
+	// too deep, treat it as cyclic
+	c.Cyclic = true
+	return nil
+}
+
+func (c *TypeChain) contains(td *parser.Typedef) bool {
+	for _, link := range c.Typedefs {
+		if link.Typedef == td {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats chain like `A -> base.B -> list<C>`. Typedefs declared in other files than the
+// first typedef are prefixed with their include name
+func (c *TypeChain) String() string {
+	items := make([]string, 0, len(c.Typedefs)+1)
+	for _, link := range c.Typedefs {
+		name := ""
+		if link.Typedef.Alias != nil && link.Typedef.Alias.Name != nil {
+			name = link.Typedef.Alias.Name.Text
+		}
+		if link.File != c.Typedefs[0].File {
+			name = lsputils.GetIncludeName(link.File) + "." + name
+		}
+		items = append(items, name)
+	}
+	if !c.Cyclic && c.Target != nil && c.Target.TypeName != nil {
+		items = append(items, format.MustFormatFieldType(c.Target))
+	}
+
+	return strings.Join(items, " -> ")
+}
+
+// LookupTypeName finds struct, union, exception, enum or typedef by type name like `User` or `base.User`
+func LookupTypeName(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, typeName string) (uri.URI, *parser.Document, parser.Node, error) {
+	include, identifier := lsputils.ParseIdent(file, ast.Includes, typeName)
+	dstFile := file
+	if include != "" {
+		path := lsputils.GetIncludePath(ast, include)
+		if path == "" {
+			return "", nil, nil, nil
+		}
+		dstFile = lsputils.IncludeURI(file, path)
+	}
+
+	pf, err := ss.Parse(ctx, dstFile)
+	if err != nil {
+		return dstFile, nil, nil, err
+	}
+	dstAst := pf.AST()
+	if dstAst == nil {
+		return dstFile, nil, nil, nil
+	}
+
+	if node := GetStructNode(dstAst, identifier); node != nil {
+		return dstFile, dstAst, node, nil
+	}
+	if node := GetUnionNode(dstAst, identifier); node != nil {
+		return dstFile, dstAst, node, nil
+	}
+	if node := GetExceptionNode(dstAst, identifier); node != nil {
+		return dstFile, dstAst, node, nil
+	}
+	if node := GetEnumNode(dstAst, identifier); node != nil {
+		return dstFile, dstAst, node, nil
+	}
+	if node := GetTypedefNode(dstAst, identifier); node != nil {
+		return dstFile, dstAst, node, nil
+	}
+
+	return dstFile, dstAst, nil, nil
+}
+
+// ResolveEnumValue finds enum of enum value reference like `Status.OK` or `base.Status.OK`
+func ResolveEnumValue(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, value string) (uri.URI, *parser.Enum, *parser.EnumValue, error) {
+	include, identifier := lsputils.ParseIdent(file, ast.Includes, value)
+	dstFile := file
+	if include != "" {
+		path := lsputils.GetIncludePath(ast, include)
+		if path == "" {
+			return "", nil, nil, nil
+		}
+		dstFile = lsputils.IncludeURI(file, path)
+	}
+
+	pf, err := ss.Parse(ctx, dstFile)
+	if err != nil {
+		return dstFile, nil, nil, err
+	}
+
+	enum := GetEnumNodeByEnumValue(pf.AST(), identifier)
+	if enum == nil {
+		return dstFile, nil, nil, nil
+	}
+	id := GetEnumValueIdentifierNode(pf.AST(), identifier)
+	for _, v := range enum.Values {
+		if v.Name == id {
+			return dstFile, enum, v, nil
+		}
+	}
+
+	return dstFile, enum, nil, nil
+}
+
+// ResolveService finds service by name like `BaseService` or `base.BaseService`.
+// node is struct, union, exception, enum or typedef if name refers to a type instead of service
+func ResolveService(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, name string) (uri.URI, *parser.Document, *parser.Service, parser.Node, error) {
+	include, identifier := lsputils.ParseIdent(file, ast.Includes, name)
+	dstFile := file
+	if include != "" {
+		path := lsputils.GetIncludePath(ast, include)
+		if path == "" {
+			return "", nil, nil, nil, nil
+		}
+		dstFile = lsputils.IncludeURI(file, path)
+	}
+
+	pf, err := ss.Parse(ctx, dstFile)
+	if err != nil {
+		return dstFile, nil, nil, nil, err
+	}
+	if pf.AST() == nil {
+		return dstFile, nil, nil, nil, nil
+	}
+
+	if svc := GetServiceNode(pf.AST(), identifier); svc != nil {
+		return dstFile, pf.AST(), svc, nil, nil
+	}
+
+	_, _, node, err := LookupTypeName(ctx, ss, file, ast, name)
+	return dstFile, pf.AST(), nil, node, err
+}
+
+// ForEachScope calls fn with file itself and each included file. prefix is include name with dot
+func ForEachScope(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, fn func(prefix string, scopeFile uri.URI, doc *parser.Document)) {
+	fn("", file, ast)
+	for _, inc := range ast.Includes {
+		if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+			continue
+		}
+		includeURI := lsputils.IncludeURI(file, inc.Path.Value.Text)
+		pf, err := ss.Parse(ctx, includeURI)
+		if err != nil || pf.AST() == nil {
+			continue
+		}
+		fn(inc.Name()+".", includeURI, pf.AST())
+	}
+}
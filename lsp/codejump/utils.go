@@ -193,6 +193,24 @@ func GetServiceNode(ast *parser.Document, name string) *parser.Service {
 	return nil
 }
 
+// GetIncludeNode include "base.thrift", base is the name
+func GetIncludeNode(cur uri.URI, ast *parser.Document, name string) *parser.Include {
+	if ast == nil {
+		return nil
+	}
+
+	for _, inc := range ast.Includes {
+		if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+			continue
+		}
+		if lsputils.GetIncludeName(lsputils.IncludeURI(cur, inc.Path.Value.Text)) == name {
+			return inc
+		}
+	}
+
+	return nil
+}
+
 func jump(file uri.URI, node parser.Node) protocol.Location {
 	rng := lsputils.ASTNodeToRange(node)
 	return protocol.Location{
@@ -232,3 +250,26 @@ func IsContainerType(t string) bool {
 	_, ok := containerType[t]
 	return ok
 }
+
+// DefinitionIdentifier returns kind and name identifier of top level definition. It returns empty
+// kind and nil for other nodes
+func DefinitionIdentifier(node parser.Node) (string, *parser.Identifier) {
+	switch n := node.(type) {
+	case *parser.Struct:
+		return "struct", n.Identifier
+	case *parser.Union:
+		return "union", n.Name
+	case *parser.Exception:
+		return "exception", n.Name
+	case *parser.Enum:
+		return "enum", n.Name
+	case *parser.Typedef:
+		return "typedef", n.Alias
+	case *parser.Const:
+		return "const", n.Name
+	case *parser.Service:
+		return "service", n.Name
+	}
+
+	return "", nil
+}
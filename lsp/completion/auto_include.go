@@ -0,0 +1,98 @@
+package completion
+
+import (
+	"fmt"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// workspaceCandidates returns candidates defined in workspace files out of scope. They are qualified
+// by include name, and include of their file is added when selected
+func workspaceCandidates(ss *cache.Snapshot, file uri.URI, ast *parser.Document, cc completionContext, scope map[uri.URI]struct{}) []Candidate {
+	prefixes := make(map[string]struct{})
+	for _, inc := range ast.Includes {
+		if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+			continue
+		}
+		prefixes[inc.Name()] = struct{}{}
+	}
+	// including files which include current file makes include cycle
+	dependents := make(map[uri.URI]struct{})
+	for _, dependent := range ss.Graph().IncludedBy(file) {
+		dependents[dependent] = struct{}{}
+	}
+
+	var res []Candidate
+	for _, sym := range ss.Symbols() {
+		if _, ok := scope[sym.File]; ok {
+			continue
+		}
+		if _, ok := dependents[sym.File]; ok {
+			continue
+		}
+		prefix := lsputils.GetIncludeName(sym.File)
+		// prefix is used by another include
+		if _, ok := prefixes[prefix]; ok {
+			continue
+		}
+
+		candidate, ok := symbolCandidate(cc, file, prefix+".", sym, scoreWorkspace)
+		if !ok {
+			continue
+		}
+		includePath := lsputils.RelativeIncludePath(file, sym.File)
+		candidate.detail = fmt.Sprintf("%s (include %q)", candidate.detail, includePath)
+		candidate.additionalEdits = []protocol.TextEdit{includeEdit(ast, includePath)}
+		res = append(res, candidate)
+	}
+
+	return res
+}
+
+// includeEdit inserts include of includePath after the last include. If there is no include, it
+// is inserted before the first header or definition
+func includeEdit(ast *parser.Document, includePath string) protocol.TextEdit {
+	text := fmt.Sprintf("include %q\n", includePath)
+	var line uint32
+	if len(ast.Includes) > 0 {
+		// line of node is 1-based, so it is the next line in lsp position
+		line = uint32(ast.Includes[len(ast.Includes)-1].End().Line)
+	} else {
+		for _, node := range ast.Nodes {
+			if node.Type() == "Comment" {
+				continue
+			}
+			switch node.(type) {
+			case *parser.Namespace, *parser.CPPInclude:
+				// comments above first header are usually file comments
+				line = uint32(node.Pos().Line - 1)
+			default:
+				// keep doc comments of definition attached, separate include from it
+				line = uint32(startLine(node) - 1)
+				text += "\n"
+			}
+			break
+		}
+	}
+
+	pos := protocol.Position{Line: line}
+	return protocol.TextEdit{
+		Range:   protocol.Range{Start: pos, End: pos},
+		NewText: text,
+	}
+}
+
+// startLine returns the first line of node, including its doc comments
+func startLine(node parser.Node) int {
+	line := node.Pos().Line
+	for _, child := range node.Children() {
+		if child != nil && !child.IsBadNode() && child.Pos().Line > 0 && child.Pos().Line < line {
+			line = child.Pos().Line
+		}
+	}
+	return line
+}
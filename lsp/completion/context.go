@@ -0,0 +1,231 @@
+package completion
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/joyme123/thrift-ls/parser"
+)
+
+type contextKind int
+
+const (
+	contextUnknown contextKind = iota
+	// contextType is position of field type, typedef, const type or container element type
+	contextType
+	// contextReturnType is position of function return type, void is allowed
+	contextReturnType
+	// contextException is position of field type in throws
+	contextException
+	// contextValue is position of const value or default value of field
+	contextValue
+	// contextService is position of service name after extends
+	contextService
+	// contextNamespace is position of language scope after namespace
+	contextNamespace
+	// contextFieldLine is start of a new field in struct, union, exception, arguments or throws
+	contextFieldLine
+	// contextEnumLine is start of a new value in enum
+	contextEnumLine
+)
+
+type completionContext struct {
+	kind contextKind
+	// expectedType is type name of const or field if kind is contextValue
+	expectedType string
+	// name is name of service or const being declared. It can't refer to itself
+	name string
+	// owner is keyword of definition owning the new line if kind is contextFieldLine or
+	// contextEnumLine. It is struct, union, exception, enum, function or throws
+	owner string
+	// open is offset of bracket enclosing the new line
+	open int
+}
+
+var (
+	namespaceRegexp  = regexp.MustCompile(`^\s*namespace\s+$`)
+	extendsRegexp    = regexp.MustCompile(`\bservice\s+(\w+)\s+extends\s+$`)
+	fieldTypeRegexp  = regexp.MustCompile(`^\s*-?\d+\s*:\s*((required|optional)\s+)?$`)
+	returnTypeRegexp = regexp.MustCompile(`^\s*(oneway\s+)?$`)
+	valueRegexp      = regexp.MustCompile(`([\w.]+)\s+\w+\s*=\s*$`)
+	topTypeRegexp    = regexp.MustCompile(`^\s*(typedef|const)\s+$`)
+	topValueRegexp   = regexp.MustCompile(`^\s*const\s+([\w.]+)\s+(\w+)\s*=\s*$`)
+	definitionRegexp = regexp.MustCompile(`\b(struct|union|exception|service|enum)\s+\w+[^{}]*$`)
+)
+
+// detectContext detects context of word starting at offset start. Definition enclosing position
+// is found by node path. Ast is incomplete while typing, so position in definition is detected
+// by text before word
+func detectContext(nodePath []parser.Node, content []byte, start int) completionContext {
+	for _, node := range nodePath {
+		if node.Type() == "Comment" || node.Type() == "Literal" {
+			return completionContext{}
+		}
+	}
+
+	line := string(content[strings.LastIndexByte(string(content[:start]), '\n')+1 : start])
+	if strings.Contains(line, "//") || strings.Contains(line, "#") || strings.Contains(line, "/*") {
+		return completionContext{}
+	}
+	if namespaceRegexp.MatchString(line) {
+		return completionContext{kind: contextNamespace}
+	}
+	if m := extendsRegexp.FindStringSubmatch(line); m != nil {
+		return completionContext{kind: contextService, name: m[1]}
+	}
+
+	open := enclosingBracket(content, start)
+	if open < 0 {
+		// top level
+		if topTypeRegexp.MatchString(line) {
+			return completionContext{kind: contextType}
+		}
+		if m := topValueRegexp.FindStringSubmatch(line); m != nil {
+			return completionContext{kind: contextValue, expectedType: m[1], name: m[2]}
+		}
+		return completionContext{}
+	}
+
+	// text after last separator in brackets
+	segment := string(content[open+1 : start])
+	if idx := strings.LastIndexAny(segment, ",;\n"); idx >= 0 {
+		segment = segment[idx+1:]
+	}
+	header := strings.TrimSpace(string(content[:open]))
+
+	switch content[open] {
+	case '<':
+		if strings.TrimSpace(segment) == "" {
+			return completionContext{kind: contextType}
+		}
+	case '[':
+		if strings.TrimSpace(segment) == "" {
+			return completionContext{kind: contextValue}
+		}
+	case '(':
+		if strings.TrimSpace(segment) == "" && isFunctionParen(nodePath, content, open) {
+			owner := "function"
+			if strings.HasSuffix(header, "throws") {
+				owner = "throws"
+			}
+			return completionContext{kind: contextFieldLine, owner: owner, open: open}
+		}
+		if m := valueRegexp.FindStringSubmatch(segment); m != nil {
+			return completionContext{kind: contextValue, expectedType: m[1]}
+		}
+		if fieldTypeRegexp.MatchString(segment) {
+			if strings.HasSuffix(header, "throws") {
+				return completionContext{kind: contextException}
+			}
+			return completionContext{kind: contextType}
+		}
+	case '{':
+		if header != "" && strings.ContainsAny(header[len(header)-1:], "=:,[") {
+			// map value
+			if idx := strings.LastIndex(segment, ":"); idx >= 0 {
+				segment = segment[idx+1:]
+			}
+			if strings.TrimSpace(segment) == "" {
+				return completionContext{kind: contextValue}
+			}
+			return completionContext{}
+		}
+
+		definition := enclosingDefinition(nodePath, header)
+		if strings.TrimSpace(segment) == "" {
+			switch definition {
+			case "struct", "union", "exception":
+				return completionContext{kind: contextFieldLine, owner: definition, open: open}
+			case "enum":
+				return completionContext{kind: contextEnumLine, owner: definition, open: open}
+			}
+		}
+
+		switch definition {
+		case "struct", "union", "exception":
+			if m := valueRegexp.FindStringSubmatch(segment); m != nil {
+				return completionContext{kind: contextValue, expectedType: m[1]}
+			}
+			if fieldTypeRegexp.MatchString(segment) {
+				return completionContext{kind: contextType}
+			}
+		case "service":
+			if returnTypeRegexp.MatchString(segment) {
+				return completionContext{kind: contextReturnType}
+			}
+		}
+	}
+
+	return completionContext{}
+}
+
+// isFunctionParen reports whether bracket at open encloses arguments or throws of function.
+// Parentheses of annotations follow a closing bracket or a field
+func isFunctionParen(nodePath []parser.Node, content []byte, open int) bool {
+	header := strings.TrimSpace(string(content[:open]))
+	if header == "" || wordStart([]byte(header), len(header)) == len(header) {
+		return false
+	}
+
+	outer := enclosingBracket(content, open)
+	if outer < 0 || content[outer] != '{' {
+		return false
+	}
+	return enclosingDefinition(nodePath, strings.TrimSpace(string(content[:outer]))) == "service"
+}
+
+// enclosingDefinition returns keyword of definition enclosing position. If node path stops at
+// document because of syntax errors, keyword is searched in header before brace
+func enclosingDefinition(nodePath []parser.Node, header string) string {
+	for _, node := range nodePath {
+		switch node.(type) {
+		case *parser.Struct:
+			return "struct"
+		case *parser.Union:
+			return "union"
+		case *parser.Exception:
+			return "exception"
+		case *parser.Service:
+			return "service"
+		case *parser.Enum:
+			return "enum"
+		}
+	}
+
+	if m := definitionRegexp.FindStringSubmatch(header); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// enclosingBracket returns offset of the innermost unclosed bracket before end. It returns -1 at top level
+func enclosingBracket(content []byte, end int) int {
+	depth := 0
+	for i := end - 1; i >= 0; i-- {
+		switch content[i] {
+		case ')', ']', '}', '>':
+			depth++
+		case '(', '[', '{', '<':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// wordStart returns start offset of identifier ending at end. Identifier may contain dots like `base.User`
+func wordStart(content []byte, end int) int {
+	i := end
+	for i > 0 {
+		ch := content[i-1]
+		if ch == '_' || ch == '.' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') {
+			i--
+			continue
+		}
+		break
+	}
+	return i
+}
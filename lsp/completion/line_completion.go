@@ -0,0 +1,289 @@
+package completion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/format"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+var (
+	fieldIDRegexp   = regexp.MustCompile(`^\s*(-?\d+)\s*:`)
+	enumValueRegexp = regexp.MustCompile(`^\s*\w+\s*(=\s*(-?\d+))?`)
+)
+
+// lineCandidates returns snippets of a new field with the next free id, or a new enum value with
+// the next value. For fields, fields with name prefixed by word in workspace can be copied too
+func lineCandidates(ss *cache.Snapshot, file uri.URI, nodePath []parser.Node, content []byte, cc completionContext, start int, word string) []Candidate {
+	var res []Candidate
+	snippet := func(label string, insertText string) Candidate {
+		return Candidate{
+			showText:   label,
+			insertText: insertText,
+			format:     protocol.InsertTextFormatSnippet,
+			kind:       protocol.CompletionItemKindSnippet,
+			// keep snippets in order
+			score: scoreLocal - len(res),
+		}
+	}
+
+	// word being typed isn't a field or value yet
+	rest := make([]byte, 0, len(content)-len(word))
+	rest = append(append(rest, content[:start]...), content[start+len(word):]...)
+
+	if cc.kind == contextEnumLine {
+		value := nextEnumValue(nodePath, rest, cc.open, start)
+		// word typed is kept as name
+		if word != "" && !isDigits(word) {
+			return []Candidate{snippet(fmt.Sprintf("%s = %d", word, value), fmt.Sprintf("%s = %d", word, value))}
+		}
+		return []Candidate{snippet(fmt.Sprintf("NAME = %d", value), fmt.Sprintf("${1:NAME} = %d", value))}
+	}
+
+	fields, ok := ownerFields(nodePath, cc.owner)
+	var id int
+	if ok {
+		id = nextFieldID(fields)
+	} else {
+		id = nextFieldIDByText(rest, cc.open)
+	}
+
+	var requiredness []string
+	switch cc.owner {
+	case "struct", "exception":
+		requiredness = []string{"required ", "optional ", ""}
+	case "union":
+		// union fields can't be required
+		requiredness = []string{"optional ", ""}
+	default:
+		requiredness = []string{""}
+	}
+
+	for _, req := range requiredness {
+		res = append(res, snippet(fmt.Sprintf("%d: %stype name", id, req), fmt.Sprintf("%d: %s${1:type} ${2:name}", id, req)))
+	}
+
+	if cc.owner != "throws" && word != "" && !isDigits(word) {
+		res = append(res, copyFieldCandidates(ss, file, fields, cc.owner, word, id)...)
+	}
+
+	return res
+}
+
+// copyFieldCandidates returns fields in workspace with name prefixed by word, renumbered by id.
+// Fields in other files are copied only if their types are base types, because type names may
+// not be valid in current file
+func copyFieldCandidates(ss *cache.Snapshot, file uri.URI, fields []*parser.Field, owner string, word string, id int) []Candidate {
+	existed := make(map[string]struct{})
+	for _, field := range fields {
+		if !field.BadNode && field.Identifier != nil && field.Identifier.Name != nil {
+			existed[field.Identifier.Name.Text] = struct{}{}
+		}
+	}
+
+	var res []Candidate
+	seen := make(map[string]struct{})
+	for _, sym := range ss.Symbols() {
+		var symFields []*parser.Field
+		switch n := sym.Node.(type) {
+		case *parser.Struct:
+			symFields = n.Fields
+		case *parser.Union:
+			symFields = n.Fields
+		case *parser.Exception:
+			symFields = n.Fields
+		default:
+			continue
+		}
+
+		for _, field := range symFields {
+			if field.BadNode || field.ChildrenBadNode() || field.Index == nil || field.FieldType == nil ||
+				field.Identifier == nil || field.Identifier.Name == nil {
+				continue
+			}
+			name := field.Identifier.Name.Text
+			if _, ok := existed[name]; ok {
+				continue
+			}
+			if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(word)) {
+				continue
+			}
+			if sym.File != file && !isBaseFieldType(field.FieldType) {
+				continue
+			}
+
+			text := formatCopiedField(field, owner, id)
+			if _, ok := seen[text]; ok {
+				continue
+			}
+			seen[text] = struct{}{}
+			res = append(res, Candidate{
+				showText:   text,
+				insertText: text,
+				format:     protocol.InsertTextFormatPlainText,
+				detail:     fmt.Sprintf("copy field from %s %s", sym.Kind, sym.Name),
+				doc:        field.Doc(),
+				kind:       protocol.CompletionItemKindField,
+				score:      scoreInclude,
+			})
+		}
+	}
+
+	return res
+}
+
+// formatCopiedField formats field in one line with new id. Comments are dropped, and required
+// keyword is dropped if owner doesn't support it
+func formatCopiedField(field *parser.Field, owner string, id int) string {
+	copied := *field
+	copied.Index = &parser.FieldIndex{ColonKeyword: field.Index.ColonKeyword, Value: id, Location: field.Index.Location}
+	copied.Comments = nil
+	copied.EndLineComments = nil
+	copied.ListSeparatorKeyword = nil
+	if copied.RequiredKeyword != nil && owner != "struct" && owner != "exception" &&
+		format.MustFormatKeyword(copied.RequiredKeyword.Keyword) == "required" {
+		copied.RequiredKeyword = nil
+	}
+
+	return format.MustFormatField(&copied, " ", "", true)
+}
+
+// ownerFields returns fields of definition owning the new line. It returns false if owner isn't
+// found in node path, for example ast is broken while typing
+func ownerFields(nodePath []parser.Node, owner string) ([]*parser.Field, bool) {
+	for i := len(nodePath) - 1; i >= 0; i-- {
+		switch n := nodePath[i].(type) {
+		case *parser.Struct:
+			return n.Fields, owner == "struct"
+		case *parser.Union:
+			return n.Fields, owner == "union"
+		case *parser.Exception:
+			return n.Fields, owner == "exception"
+		case *parser.Throws:
+			return n.Fields, owner == "throws"
+		case *parser.Function:
+			if owner == "function" && !n.BadNode {
+				return n.Arguments, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// nextFieldID returns max field id plus one
+func nextFieldID(fields []*parser.Field) int {
+	max := 0
+	for _, field := range fields {
+		if field.Index != nil && !field.Index.BadNode && field.Index.Value > max {
+			max = field.Index.Value
+		}
+	}
+	return max + 1
+}
+
+// nextFieldIDByText returns max field id in bracket at open plus one
+func nextFieldIDByText(content []byte, open int) int {
+	max := 0
+	for _, segment := range bracketSegments(content, open) {
+		if m := fieldIDRegexp.FindStringSubmatch(segment); m != nil {
+			if id, err := strconv.Atoi(m[1]); err == nil && id > max {
+				max = id
+			}
+		}
+	}
+	return max + 1
+}
+
+// nextEnumValue returns max enum value plus one. Value of the first enum value is 0. Value being
+// typed at start is skipped
+func nextEnumValue(nodePath []parser.Node, content []byte, open int, start int) int64 {
+	for i := len(nodePath) - 1; i >= 0; i-- {
+		enum, ok := nodePath[i].(*parser.Enum)
+		if !ok {
+			continue
+		}
+		var next int64
+		for _, v := range enum.Values {
+			if !v.BadNode && v.Pos().Offset != start && v.Value >= next {
+				next = v.Value + 1
+			}
+		}
+		return next
+	}
+
+	// values without explicit value are previous value plus one
+	var next, cur int64 = 0, -1
+	for _, segment := range bracketSegments(content, open) {
+		m := enumValueRegexp.FindStringSubmatch(segment)
+		if m == nil {
+			continue
+		}
+		if m[2] != "" {
+			cur, _ = strconv.ParseInt(m[2], 10, 64)
+		} else {
+			cur++
+		}
+		if cur >= next {
+			next = cur + 1
+		}
+	}
+	return next
+}
+
+// bracketSegments splits text in bracket at open by separators and lines. Text in nested
+// brackets is skipped
+func bracketSegments(content []byte, open int) []string {
+	var res []string
+	depth := 0
+	start := open + 1
+	for i := open + 1; i < len(content); i++ {
+		switch content[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case ',', ';', '\n':
+			if depth == 0 {
+				res = append(res, string(content[start:i]))
+				start = i + 1
+			}
+			continue
+		default:
+			continue
+		}
+		if depth < 0 {
+			res = append(res, string(content[start:i]))
+			return res
+		}
+	}
+
+	return append(res, string(content[start:]))
+}
+
+// isBaseFieldType reports whether ft consists of base types and containers only
+func isBaseFieldType(ft *parser.FieldType) bool {
+	if ft == nil || ft.TypeName == nil {
+		return true
+	}
+	if !codejump.IsBasicType(ft.TypeName.Name) && !codejump.IsContainerType(ft.TypeName.Name) {
+		return false
+	}
+	return isBaseFieldType(ft.KeyType) && isBaseFieldType(ft.ValueType)
+}
+
+func isDigits(s string) bool {
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
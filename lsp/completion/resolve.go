@@ -0,0 +1,103 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// resolveKindBuiltin is kind of ResolveData of base types and keywords
+const resolveKindBuiltin = "builtin"
+
+// ResolveData is attached to completion item as Data. Client sends it back in completionItem/resolve
+// request, then documentation of item is resolved lazily
+type ResolveData struct {
+	// File is the file where definition is declared. It is empty for builtin keywords
+	File uri.URI `json:"file,omitempty"`
+	// From is the file requesting completion, file of definition is shown relative to it
+	From uri.URI `json:"from,omitempty"`
+	// Name is name of symbol in File or builtin keyword
+	Name string `json:"name"`
+	// Kind is kind of symbol or builtin
+	Kind string `json:"kind"`
+}
+
+// symbolData returns resolve data of symbol completed in file from
+func symbolData(from uri.URI, sym *cache.Symbol) *ResolveData {
+	return &ResolveData{File: sym.File, From: from, Name: sym.Name, Kind: sym.Kind}
+}
+
+// builtinData returns resolve data of keyword if it has builtin documentation
+func builtinData(keyword string) *ResolveData {
+	if codejump.BuiltinDoc(keyword) == "" {
+		return nil
+	}
+	return &ResolveData{Name: keyword, Kind: resolveKindBuiltin}
+}
+
+// ParseResolveData converts data of completion item to ResolveData. Data is a json object
+// if it is sent back by client
+func ParseResolveData(data interface{}) *ResolveData {
+	switch v := data.(type) {
+	case nil:
+		return nil
+	case *ResolveData:
+		return v
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	res := &ResolveData{}
+	if err := json.Unmarshal(raw, res); err != nil || res.Name == "" {
+		return nil
+	}
+	return res
+}
+
+// Resolve returns documentation in markdown of completion item. Definitions are shown in thrift
+// code block, followed by doc comment and the file declaring them
+func Resolve(ctx context.Context, ss *cache.Snapshot, data *ResolveData) (string, error) {
+	if data.Kind == resolveKindBuiltin {
+		return codejump.BuiltinDoc(data.Name), nil
+	}
+
+	pf, err := ss.Parse(ctx, data.File)
+	if err != nil {
+		return "", err
+	}
+	if pf.AST() == nil {
+		return "", nil
+	}
+
+	for _, sym := range cache.DocumentSymbols(data.File, pf.AST()) {
+		if sym.Name != data.Name || sym.Kind != data.Kind {
+			continue
+		}
+
+		code := codejump.FormatDefinition(sym.Node)
+		if v, ok := sym.Node.(*parser.EnumValue); ok {
+			code = fmt.Sprintf("%s = %d", sym.Name, v.Value)
+		}
+
+		doc := sym.Doc
+		if data.From != "" && data.From != data.File {
+			source := fmt.Sprintf("From `%s`", lsputils.RelativeIncludePath(data.From, data.File))
+			if doc != "" {
+				doc = doc + "\n\n" + source
+			} else {
+				doc = source
+			}
+		}
+		return codejump.HoverContent(code, doc), nil
+	}
+
+	return "", nil
+}
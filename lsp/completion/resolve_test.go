@@ -0,0 +1,58 @@
+package completion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	base := `// user of system
+struct User {
+  1: string name
+}
+
+enum Status {
+  // everything is ok
+  OK = 1
+}`
+
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{URI: "file:///tmp/common/base.thrift", Content: []byte(base), From: cache.FileChangeTypeDidOpen},
+	})
+
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{
+			name: "struct",
+			data: &ResolveData{File: "file:///tmp/common/base.thrift", From: "file:///tmp/api/main.thrift", Name: "User", Kind: cache.SymbolStruct},
+			want: "```thrift\nstruct User {\n    1: string name\n}\n```\n\nuser of system\n\nFrom `../common/base.thrift`",
+		},
+		{
+			name: "enum value sent back by client",
+			data: map[string]interface{}{"file": "file:///tmp/common/base.thrift", "from": "file:///tmp/common/base.thrift", "name": "Status.OK", "kind": cache.SymbolEnumValue},
+			want: "```thrift\nStatus.OK = 1\n```\n\neverything is ok",
+		},
+		{
+			name: "builtin",
+			data: builtinData("i32"),
+			want: "A 32-bit signed integer.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := ParseResolveData(tt.data)
+			if assert.NotNil(t, data) {
+				got, err := Resolve(context.TODO(), ss, data)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -2,29 +2,310 @@ package completion
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/joyme123/protocol"
 	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/parser"
+	log "github.com/sirupsen/logrus"
+	"go.lsp.dev/uri"
 )
 
 type Interface interface {
 	Completion(ctx context.Context, ss *cache.Snapshot, cmp *CompletionRequest) ([]*CompletionItem, protocol.Range, error)
 }
 
-// SemanticBasedCompletion generates completion list based on semantic. It is more precisely than token based completion
-// TODO(jpf)
+var DefaultSemanticCompletion Interface = &SemanticBasedCompletion{}
+
+// SemanticBasedCompletion generates completion list based on semantic. It is more precisely than token based completion.
+// It falls back to token based completion if context of position is unknown, like field names
 type SemanticBasedCompletion struct {
 }
 
+// scores of candidates, higher is better
+const (
+	scoreLocal    = 100
+	scoreBaseType = 95
+	scoreInclude  = 90
+	// scoreWorkspace is score of candidates in files not included yet
+	scoreWorkspace = 80
+	// scoreExpected is added to enum values of expected type
+	scoreExpected = 20
+	// scorePrefix is added to candidates matching prefix in case
+	scorePrefix = 5
+)
+
+func (c *SemanticBasedCompletion) Completion(ctx context.Context, ss *cache.Snapshot, cmp *CompletionRequest) ([]*CompletionItem, protocol.Range, error) {
+	rng := protocol.Range{
+		Start: protocol.Position{
+			Line:      cmp.Pos.Line,
+			Character: cmp.Pos.Character,
+		},
+		End: protocol.Position{
+			Line:      cmp.Pos.Line,
+			Character: cmp.Pos.Character,
+		},
+	}
+
+	parsedFile, err := ss.Parse(ctx, cmp.Fh.URI())
+	if err != nil {
+		return nil, rng, err
+	}
+
+	if parsedFile.AST() == nil {
+		return nil, rng, fmt.Errorf("parser ast failed")
+	}
+
+	pos, err := parsedFile.Mapper().LSPPosToParserPosition(cmp.Pos)
+	if err != nil {
+		return nil, rng, err
+	}
+
+	content, err := cmp.Fh.Content()
+	if err != nil {
+		return nil, rng, err
+	}
+	if pos.Offset > len(content) {
+		return nil, rng, fmt.Errorf("invalid position")
+	}
+
+	start := wordStart(content, pos.Offset)
+	qualifier, word := "", string(content[start:pos.Offset])
+	if idx := strings.LastIndex(word, "."); idx >= 0 {
+		qualifier, word = word[:idx], word[idx+1:]
+	}
+
+	nodePath := parser.SearchNodePathByPosition(parsedFile.AST(), pos)
+	cc := detectContext(nodePath, content, start)
+	log.Debugln("semantic completion context:", cc.kind, "qualifier:", qualifier, "word:", word)
+	if cc.kind == contextUnknown {
+		return DefaultTokenCompletion.Completion(ctx, ss, cmp)
+	}
+
+	rng.Start.Character = rng.Start.Character - uint32(len(word))
+
+	var candidates []Candidate
+	if cc.kind == contextFieldLine || cc.kind == contextEnumLine {
+		// snippets are filtered by client
+		candidates = lineCandidates(ss, cmp.Fh.URI(), nodePath, content, cc, start, word)
+	} else {
+		candidates = c.candidates(ctx, ss, cmp.Fh.URI(), parsedFile.AST(), cc)
+		candidates = filterCandidates(candidates, qualifier, word)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score == candidates[j].score {
+			return candidates[i].showText < candidates[j].showText
+		}
+		return candidates[i].score > candidates[j].score
+	})
+
+	res := make([]*CompletionItem, 0, len(candidates))
+	for i := range candidates {
+		res = append(res, BuildCompletionItem(candidates[i]))
+	}
+
+	return res, rng, nil
+}
+
+// candidates returns candidates valid in context. showText of candidate is full name
+// like `base.Status.OK`, it is trimmed by filterCandidates
+func (c *SemanticBasedCompletion) candidates(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, cc completionContext) []Candidate {
+	var res []Candidate
+	add := func(name string, kind protocol.CompletionItemKind, detail string, score int) {
+		res = append(res, Candidate{
+			showText:   name,
+			insertText: name,
+			format:     protocol.InsertTextFormatPlainText,
+			detail:     detail,
+			kind:       kind,
+			score:      score,
+			data:       builtinData(name),
+		})
+	}
+
+	switch cc.kind {
+	case contextNamespace:
+		for _, scope := range namespaceScopes {
+			add(scope, protocol.CompletionItemKindModule, "namespace scope", scoreLocal)
+		}
+		return res
+	case contextType, contextReturnType:
+		if cc.kind == contextReturnType {
+			add("void", protocol.CompletionItemKindKeyword, "void", scoreBaseType)
+		}
+		for _, name := range baseTypes {
+			add(name, protocol.CompletionItemKindKeyword, name, scoreBaseType)
+		}
+		for _, name := range []string{"list", "set", "map"} {
+			res = append(res, Candidate{
+				showText:   name,
+				insertText: containerSnippets[name],
+				format:     protocol.InsertTextFormatSnippet,
+				detail:     name,
+				kind:       protocol.CompletionItemKindKeyword,
+				score:      scoreBaseType,
+				data:       builtinData(name),
+			})
+		}
+	}
+
+	scope := make(map[uri.URI]struct{})
+	codejump.ForEachScope(ctx, ss, file, ast, func(prefix string, scopeFile uri.URI, doc *parser.Document) {
+		scope[scopeFile] = struct{}{}
+		score := scoreLocal
+		if prefix != "" {
+			score = scoreInclude
+		}
+		for _, sym := range cache.DocumentSymbols(scopeFile, doc) {
+			if candidate, ok := symbolCandidate(cc, file, prefix, sym, score); ok {
+				res = append(res, candidate)
+			}
+		}
+	})
+	res = append(res, workspaceCandidates(ss, file, ast, cc, scope)...)
+
+	return res
+}
+
+// symbolCandidate returns candidate of sym if it is valid in context. prefix is include name with dot
+func symbolCandidate(cc completionContext, file uri.URI, prefix string, sym *cache.Symbol, score int) (Candidate, bool) {
+	switch sym.Kind {
+	case cache.SymbolStruct, cache.SymbolUnion, cache.SymbolEnum, cache.SymbolTypedef:
+		if cc.kind != contextType && cc.kind != contextReturnType {
+			return Candidate{}, false
+		}
+	case cache.SymbolException:
+		if cc.kind != contextType && cc.kind != contextReturnType && cc.kind != contextException {
+			return Candidate{}, false
+		}
+	case cache.SymbolService:
+		// service can't extend itself
+		if cc.kind != contextService || (prefix == "" && sym.Name == cc.name) {
+			return Candidate{}, false
+		}
+	case cache.SymbolConst:
+		// const can't refer to itself
+		if cc.kind != contextValue || (prefix == "" && sym.Name == cc.name) {
+			return Candidate{}, false
+		}
+	case cache.SymbolEnumValue:
+		if cc.kind != contextValue {
+			return Candidate{}, false
+		}
+	default:
+		return Candidate{}, false
+	}
+
+	name := prefix + sym.Name
+	candidate := Candidate{
+		showText:   name,
+		insertText: name,
+		format:     protocol.InsertTextFormatPlainText,
+		detail:     sym.Kind + " " + name,
+		kind:       symbolKinds[sym.Kind],
+		score:      score,
+		data:       symbolData(file, sym),
+	}
+	if v, ok := sym.Node.(*parser.EnumValue); ok {
+		candidate.detail = fmt.Sprintf("%s = %d", name, v.Value)
+		if name[:strings.LastIndex(name, ".")] == cc.expectedType {
+			candidate.score += scoreExpected
+		}
+	}
+
+	return candidate, true
+}
+
+// filterCandidates keeps candidates in qualifier and matching word, qualifier is trimmed from
+// candidates. Word matches full name or any part of name split by dot, ignoring case
+func filterCandidates(candidates []Candidate, qualifier string, word string) []Candidate {
+	var res []Candidate
+	for _, candidate := range candidates {
+		name := candidate.showText
+		if qualifier != "" {
+			if !strings.HasPrefix(name, qualifier+".") {
+				continue
+			}
+			name = name[len(qualifier)+1:]
+		}
+
+		matched := false
+		lowerWord := strings.ToLower(word)
+		for _, part := range strings.Split(name, ".") {
+			if strings.HasPrefix(strings.ToLower(part), lowerWord) {
+				matched = true
+				break
+			}
+		}
+		if !matched && !strings.HasPrefix(strings.ToLower(name), lowerWord) {
+			continue
+		}
+
+		if word != "" && strings.HasPrefix(name, word) {
+			candidate.score += scorePrefix
+		}
+		if candidate.insertText == candidate.showText {
+			candidate.insertText = name
+		}
+		candidate.showText = name
+		res = append(res, candidate)
+	}
+
+	return res
+}
+
+var symbolKinds = map[string]protocol.CompletionItemKind{
+	cache.SymbolStruct:    protocol.CompletionItemKindStruct,
+	cache.SymbolUnion:     protocol.CompletionItemKindStruct,
+	cache.SymbolException: protocol.CompletionItemKindStruct,
+	cache.SymbolEnum:      protocol.CompletionItemKindEnum,
+	cache.SymbolEnumValue: protocol.CompletionItemKindEnumMember,
+	cache.SymbolTypedef:   protocol.CompletionItemKindTypeParameter,
+	cache.SymbolConst:     protocol.CompletionItemKindConstant,
+	cache.SymbolService:   protocol.CompletionItemKindInterface,
+}
+
+var baseTypes = []string{"bool", "byte", "i8", "i16", "i32", "i64", "double", "string", "binary", "uuid"}
+
+var containerSnippets = map[string]string{
+	"list": "list<$1>",
+	"set":  "set<$1>",
+	"map":  "map<$1, $2>",
+}
+
+var namespaceScopes = []string{
+	"*", "c_glib", "cpp", "delphi", "go", "haxe", "java", "js", "lua", "netstd", "nodejs",
+	"perl", "php", "py", "py.twisted", "rb", "rs", "st", "swift", "xsd",
+}
+
 func BuildCompletionItem(candidate Candidate) *CompletionItem {
+	detail := candidate.detail
+	if detail == "" {
+		detail = candidate.showText
+	}
+	kind := candidate.kind
+	if kind == 0 {
+		kind = protocol.CompletionItemKindText
+	}
+	score := candidate.score
+	if score == 0 {
+		score = 90
+	}
+
 	return &CompletionItem{
 		Label:            candidate.showText,
-		Detail:           candidate.showText,
+		Detail:           detail,
 		InsertText:       candidate.insertText,
 		InsertTextFormat: candidate.format,
-		Kind:             protocol.CompletionItemKindText,
+		Kind:             kind,
 		Deprecated:       false,
-		Score:            90,
-		Documentation:    "",
+		Score:            score,
+		Documentation:    candidate.doc,
+
+		AdditionalTextEdits: candidate.additionalEdits,
+		Data:                candidate.data,
 	}
 }
@@ -0,0 +1,306 @@
+package completion
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/types"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func TestSemanticBasedCompletion(t *testing.T) {
+	base := `struct User {
+  1: string name
+}
+
+enum Status {
+  OK = 1,
+  FAILED = 2
+}
+
+exception BaseError {
+  1: string message
+}
+
+service BaseService {
+}
+
+const i32 MaxAge = 100`
+
+	local := `include "base.thrift"
+
+struct Point {
+  1: i32 x
+}
+
+exception NotFound {
+  1: string message
+}
+
+enum Level {
+  LOW = 1
+}
+
+typedef list<Point> Points
+`
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		kinds   []protocol.CompletionItemKind
+	}{
+		{
+			name:    "field type",
+			content: "struct A {\n  1: required Po|\n}",
+			want:    []string{"Point", "Points"},
+			kinds:   []protocol.CompletionItemKind{protocol.CompletionItemKindStruct, protocol.CompletionItemKindTypeParameter},
+		},
+		{
+			name:    "field type of include",
+			content: "struct A {\n  1: required base.|\n}",
+			want:    []string{"BaseError", "Status", "User"},
+			kinds:   []protocol.CompletionItemKind{protocol.CompletionItemKindStruct, protocol.CompletionItemKindEnum, protocol.CompletionItemKindStruct},
+		},
+		{
+			name:    "container element",
+			content: "struct A {\n  1: list<Us|> a\n}",
+			want:    []string{"base.User"},
+		},
+		{
+			name:    "base type",
+			content: "typedef i|",
+			want:    []string{"i16", "i32", "i64", "i8"},
+		},
+		{
+			name:    "return type",
+			content: "service S {\n  vo|\n}",
+			want:    []string{"void"},
+		},
+		{
+			name:    "throws",
+			content: "service S {\n  void f() throws (1: |)\n}",
+			want:    []string{"NotFound", "base.BaseError"},
+		},
+		{
+			name:    "enum value",
+			content: "struct A {\n  1: base.Status s = base.Status.|\n}",
+			want:    []string{"FAILED", "OK"},
+			kinds:   []protocol.CompletionItemKind{protocol.CompletionItemKindEnumMember, protocol.CompletionItemKindEnumMember},
+		},
+		{
+			name:    "expected enum ranks first",
+			content: "const Level l = |",
+			want:    []string{"Level.LOW", "base.MaxAge", "base.Status.FAILED", "base.Status.OK"},
+		},
+		{
+			name:    "extends",
+			content: "service S extends |",
+			want:    []string{"base.BaseService"},
+			kinds:   []protocol.CompletionItemKind{protocol.CompletionItemKindInterface},
+		},
+		{
+			name:    "namespace",
+			content: "namespace j|",
+			want:    []string{"java", "js"},
+			kinds:   []protocol.CompletionItemKind{protocol.CompletionItemKindModule, protocol.CompletionItemKindModule},
+		},
+		{
+			name:    "field name falls back to token completion",
+			content: "struct A {\n  1: required string Nam|\n}",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset := strings.Index(tt.content, "|")
+			content := local + strings.Replace(tt.content, "|", "", 1)
+			offset += len(local)
+			line := strings.Count(content[:offset], "\n")
+			character := offset - strings.LastIndex(content[:offset], "\n") - 1
+
+			ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+				{
+					URI:     "file:///tmp/base.thrift",
+					Content: []byte(base),
+					From:    cache.FileChangeTypeDidOpen,
+				},
+				{
+					URI:     "file:///tmp/main.thrift",
+					Content: []byte(content),
+					From:    cache.FileChangeTypeDidOpen,
+				},
+			})
+			fh, err := ss.ReadFile(context.TODO(), uri.URI("file:///tmp/main.thrift"))
+			assert.NoError(t, err)
+
+			items, _, err := DefaultSemanticCompletion.Completion(context.TODO(), ss, &CompletionRequest{
+				Pos: types.Position{Line: uint32(line), Character: uint32(character)},
+				Fh:  fh,
+			})
+			assert.NoError(t, err)
+
+			var labels []string
+			var kinds []protocol.CompletionItemKind
+			for _, item := range items {
+				labels = append(labels, item.Label)
+				kinds = append(kinds, item.Kind)
+			}
+			assert.Equal(t, tt.want, labels)
+			if tt.kinds != nil {
+				assert.Equal(t, tt.kinds, kinds)
+			}
+		})
+	}
+}
+
+func TestSemanticBasedCompletion_AutoInclude(t *testing.T) {
+	user := `struct User {
+  1: string name
+}`
+	shared := `struct Shared {
+}`
+	// svc includes main, user types in it can't be offered to main
+	svc := `include "main.thrift"
+
+struct UserRequest {
+}`
+
+	tests := []struct {
+		name    string
+		content string
+		want    protocol.TextEdit
+	}{
+		{
+			name:    "after includes",
+			content: "include \"shared.thrift\"\n\nstruct A {\n  1: Us|\n}",
+			want: protocol.TextEdit{
+				Range:   protocol.Range{Start: protocol.Position{Line: 1}, End: protocol.Position{Line: 1}},
+				NewText: "include \"../common/user.thrift\"\n",
+			},
+		},
+		{
+			name:    "before namespace",
+			content: "// comment\nnamespace go api\n\nstruct A {\n  1: Us|\n}",
+			want: protocol.TextEdit{
+				Range:   protocol.Range{Start: protocol.Position{Line: 1}, End: protocol.Position{Line: 1}},
+				NewText: "include \"../common/user.thrift\"\n",
+			},
+		},
+		{
+			name:    "before definition",
+			content: "struct A {\n  1: Us|\n}",
+			want: protocol.TextEdit{
+				Range:   protocol.Range{Start: protocol.Position{Line: 0}, End: protocol.Position{Line: 0}},
+				NewText: "include \"../common/user.thrift\"\n\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset := strings.Index(tt.content, "|")
+			content := strings.Replace(tt.content, "|", "", 1)
+			line := strings.Count(content[:offset], "\n")
+			character := offset - strings.LastIndex(content[:offset], "\n") - 1
+
+			ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+				{URI: "file:///tmp/common/user.thrift", Content: []byte(user), From: cache.FileChangeTypeDidOpen},
+				{URI: "file:///tmp/api/shared.thrift", Content: []byte(shared), From: cache.FileChangeTypeDidOpen},
+				{URI: "file:///tmp/api/main.thrift", Content: []byte(content), From: cache.FileChangeTypeDidOpen},
+				{URI: "file:///tmp/api/svc.thrift", Content: []byte(svc), From: cache.FileChangeTypeDidOpen},
+			})
+			fh, err := ss.ReadFile(context.TODO(), uri.URI("file:///tmp/api/main.thrift"))
+			assert.NoError(t, err)
+
+			items, _, err := DefaultSemanticCompletion.Completion(context.TODO(), ss, &CompletionRequest{
+				Pos: types.Position{Line: uint32(line), Character: uint32(character)},
+				Fh:  fh,
+			})
+			assert.NoError(t, err)
+			if assert.Len(t, items, 1) {
+				assert.Equal(t, "user.User", items[0].Label)
+				assert.Equal(t, "struct user.User (include \"../common/user.thrift\")", items[0].Detail)
+				assert.Equal(t, []protocol.TextEdit{tt.want}, items[0].AdditionalTextEdits)
+			}
+		})
+	}
+}
+
+func TestSemanticBasedCompletion_Line(t *testing.T) {
+	user := `struct User {
+  1: required i64 user_id (go.tag = "json:\"user_id\""),
+  2: optional base.Info info
+}`
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "struct field",
+			content: "struct A {\n  1: i32 a\n  3: i32 b\n  |\n}",
+			want:    []string{"4: required type name", "4: optional type name", "4: type name"},
+		},
+		{
+			name:    "union field",
+			content: "union A {\n  |\n}",
+			want:    []string{"1: optional type name", "1: type name"},
+		},
+		{
+			name:    "function argument",
+			content: "service S {\n  void f(1: i32 a, |)\n}",
+			want:    []string{"2: type name"},
+		},
+		{
+			name:    "enum value",
+			content: "enum E {\n  A = 3\n  B\n  |\n}",
+			want:    []string{"NAME = 5"},
+		},
+		{
+			name:    "enum value with name typed",
+			content: "enum E {\n  A\n  FO|\n}",
+			want:    []string{"FO = 1"},
+		},
+		{
+			name:    "copy field",
+			content: "struct A {\n  2: i32 a\n  us|\n}",
+			want: []string{"3: required type name", "3: optional type name", "3: type name",
+				`3: required i64 user_id (go.tag = "json:\"user_id\"")`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset := strings.Index(tt.content, "|")
+			content := strings.Replace(tt.content, "|", "", 1)
+			line := strings.Count(content[:offset], "\n")
+			character := offset - strings.LastIndex(content[:offset], "\n") - 1
+
+			ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+				{URI: "file:///tmp/user.thrift", Content: []byte(user), From: cache.FileChangeTypeDidOpen},
+				{URI: "file:///tmp/main.thrift", Content: []byte(content), From: cache.FileChangeTypeDidOpen},
+			})
+			fh, err := ss.ReadFile(context.TODO(), uri.URI("file:///tmp/main.thrift"))
+			assert.NoError(t, err)
+
+			items, _, err := DefaultSemanticCompletion.Completion(context.TODO(), ss, &CompletionRequest{
+				Pos: types.Position{Line: uint32(line), Character: uint32(character)},
+				Fh:  fh,
+			})
+			assert.NoError(t, err)
+
+			var labels []string
+			for _, item := range items {
+				labels = append(labels, item.Label)
+			}
+			assert.Equal(t, tt.want, labels)
+		})
+	}
+}
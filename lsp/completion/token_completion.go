@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/joyme123/protocol"
@@ -51,6 +52,17 @@ type Candidate struct {
 	showText   string
 	insertText string
 	format     protocol.InsertTextFormat
+	doc        string
+	// detail defaults to showText
+	detail string
+	// kind defaults to text
+	kind protocol.CompletionItemKind
+	// score ranks candidate, higher is better. It defaults to 90
+	score int
+	// additionalEdits are applied when candidate is selected
+	additionalEdits []protocol.TextEdit
+	// data is used to resolve documentation lazily
+	data *ResolveData
 }
 
 func (c *TokenCompletion) Completion(ctx context.Context, ss *cache.Snapshot, cmp *CompletionRequest) ([]*CompletionItem, protocol.Range, error) {
@@ -127,21 +139,39 @@ func (c *TokenCompletion) Completion(ctx context.Context, ss *cache.Snapshot, cm
 					showText:   token,
 					insertText: token,
 					format:     format,
+					data:       builtinData(token),
 				})
 			}
 		}
-		for i := range keywords {
-			searchCandidate(i, keywords[i])
+		// iterate in sorted order, map order is random
+		sortedKeywords := make([]string, 0, len(keywords))
+		for keyword := range keywords {
+			sortedKeywords = append(sortedKeywords, keyword)
+		}
+		sort.Strings(sortedKeywords)
+		sortedTokens := make([]string, 0, len(tokens))
+		for token := range tokens {
+			sortedTokens = append(sortedTokens, token)
+		}
+		sort.Strings(sortedTokens)
+
+		for _, keyword := range sortedKeywords {
+			searchCandidate(keyword, keywords[keyword])
 			if len(candidates) >= 10 {
 				break
 			}
 		}
-		for i := range tokens {
-			searchCandidate(i, protocol.InsertTextFormatPlainText)
+		for _, token := range sortedTokens {
+			searchCandidate(token, protocol.InsertTextFormatPlainText)
 			if len(candidates) >= 10 {
 				break
 			}
 		}
+
+		docs := definitionDocs(ctx, ss, cmp.Fh.URI(), parsedFile.AST())
+		for i := range candidates {
+			candidates[i].doc = docs[candidates[i].insertText]
+		}
 		log.Debugln("token prefix:", string(prefix), "candidates: ", candidates)
 	}
 
@@ -32,4 +32,10 @@ type CompletionItem struct {
 
 	// Documentation holds document text for this completion
 	Documentation string
+
+	// AdditionalTextEdits are applied besides InsertText, like adding include
+	AdditionalTextEdits []protocol.TextEdit
+
+	// Data is sent back by client to resolve documentation lazily
+	Data *ResolveData
 }
@@ -1,13 +1,18 @@
 package completion
 
 import (
+	"context"
 	"io/fs"
 	"path/filepath"
 	"strings"
 
 	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
 	"github.com/joyme123/thrift-ls/lsp/constants"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
 	log "github.com/sirupsen/logrus"
+	"go.lsp.dev/uri"
 )
 
 func ListDirAndFiles(dir, prefix string) (res []Candidate, err error) {
@@ -65,3 +70,55 @@ func ListDirAndFiles(dir, prefix string) (res []Candidate, err error) {
 
 	return
 }
+
+// definitionDocs returns doc of definitions in current file and included files. key is definition name
+func definitionDocs(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document) map[string]string {
+	docs := make(map[string]string)
+	addDocs(docs, ast)
+
+	for _, include := range ast.Includes {
+		if include.BadNode || include.Path == nil || include.Path.BadNode || include.Path.Value == nil {
+			continue
+		}
+		pf, err := ss.Parse(ctx, lsputils.IncludeURI(file, include.Path.Value.Text))
+		if err != nil || pf.AST() == nil {
+			continue
+		}
+		addDocs(docs, pf.AST())
+	}
+
+	return docs
+}
+
+func addDocs(docs map[string]string, ast *parser.Document) {
+	add := func(id *parser.Identifier, doc string) {
+		if doc == "" || id == nil || id.BadNode || id.Name == nil {
+			return
+		}
+		if _, ok := docs[id.Name.Text]; !ok {
+			docs[id.Name.Text] = doc
+		}
+	}
+
+	for _, st := range ast.Structs {
+		add(st.Identifier, st.Doc())
+	}
+	for _, union := range ast.Unions {
+		add(union.Name, union.Doc())
+	}
+	for _, exception := range ast.Exceptions {
+		add(exception.Name, exception.Doc())
+	}
+	for _, enum := range ast.Enums {
+		add(enum.Name, enum.Doc())
+	}
+	for _, svc := range ast.Services {
+		add(svc.Name, svc.Doc())
+	}
+	for _, cst := range ast.Consts {
+		add(cst.Name, cst.Doc())
+	}
+	for _, td := range ast.Typedefs {
+		add(td.Alias, td.Doc())
+	}
+}
@@ -19,7 +19,8 @@ func (s *Server) diagnostic(ctx context.Context, ss *cache.Snapshot, changeFile
 	log.Debugln("-----------diagnostic called-----------")
 	defer log.Debugln("-----------diagnostic finish-----------")
 
-	diag := diagnostic.NewDiagnostic()
+	opts := s.options.Get()
+	diag := diagnostic.NewDiagnostic(&opts.Diagnostic)
 	diagRes, err := diag.Diagnostic(ctx, ss, []uri.URI{changeFile.URI})
 	if err != nil {
 		log.Errorf("diagnostic failed: %v", err)
@@ -0,0 +1,232 @@
+package diagnostic
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// intRanges are value ranges of integer types
+var intRanges = map[string][2]int64{
+	"byte": {math.MinInt8, math.MaxInt8},
+	"i8":   {math.MinInt8, math.MaxInt8},
+	"i16":  {math.MinInt16, math.MaxInt16},
+	"i32":  {math.MinInt32, math.MaxInt32},
+	"i64":  {math.MinInt64, math.MaxInt64},
+}
+
+// checkConstValueType validates const value against field type recursively. Typedefs are resolved
+// through includes. file and ast are the file where cv is written.
+func (s *SemanticAnalysis) checkConstValueType(ctx context.Context, ss *cache.Snapshot,
+	file uri.URI, ast *parser.Document, ft *parser.FieldType, cv *parser.ConstValue) []protocol.Diagnostic {
+	return s.checkConstValueTypeIn(ctx, ss, file, ast, file, ast, ft, cv)
+}
+
+// typeFile and typeAst are the file where ft is written, they differ from file when ft comes from
+// an included typedef or struct
+func (s *SemanticAnalysis) checkConstValueTypeIn(ctx context.Context, ss *cache.Snapshot,
+	file uri.URI, ast *parser.Document, typeFile uri.URI, typeAst *parser.Document,
+	ft *parser.FieldType, cv *parser.ConstValue) (res []protocol.Diagnostic) {
+	if cv == nil || cv.BadNode || cv.ChildrenBadNode() || ft == nil || ft.BadNode || ft.TypeName == nil {
+		return nil
+	}
+
+	resolved, err := codejump.ResolveFieldType(ctx, ss, typeFile, typeAst, ft)
+	if err != nil || resolved == nil {
+		// undefined type is reported by checkTypeExist
+		return nil
+	}
+
+	valueType := constValueTypeName(cv)
+	if valueType == "identifier" {
+		return s.checkIdentifierConstValue(ctx, ss, file, ast, ft, resolved, cv)
+	}
+
+	mismatch := func() []protocol.Diagnostic {
+		return []protocol.Diagnostic{constValueDiagnostic(cv, fmt.Sprintf("expect %s but got %s", ft.TypeName.Name, valueType))}
+	}
+
+	if resolved.Definition != nil {
+		switch def := resolved.Definition.(type) {
+		case *parser.Enum:
+			if valueType != "i64" {
+				return mismatch()
+			}
+			if !enumHasValue(def, cv.Value.(int64)) {
+				return []protocol.Diagnostic{constValueDiagnostic(cv, fmt.Sprintf("%d is not a value of enum %s", cv.Value, def.Name.Name.Text))}
+			}
+			return nil
+		case *parser.Struct:
+			return s.checkStructLikeConstValue(ctx, ss, file, ast, resolved, "struct", def.Identifier, def.Fields, cv)
+		case *parser.Union:
+			return s.checkStructLikeConstValue(ctx, ss, file, ast, resolved, "union", def.Name, def.Fields, cv)
+		case *parser.Exception:
+			return s.checkStructLikeConstValue(ctx, ss, file, ast, resolved, "exception", def.Name, def.Fields, cv)
+		}
+		return nil
+	}
+
+	typeName := resolved.FieldType.TypeName.Name
+	switch typeName {
+	case "bool":
+		if valueType == "bool" {
+			return nil
+		}
+		// 0 and 1 are accepted as bool
+		if valueType == "i64" && (cv.Value.(int64) == 0 || cv.Value.(int64) == 1) {
+			return nil
+		}
+		return mismatch()
+	case "byte", "i8", "i16", "i32", "i64":
+		if valueType != "i64" {
+			return mismatch()
+		}
+		v := cv.Value.(int64)
+		rng := intRanges[typeName]
+		if v < rng[0] || v > rng[1] {
+			return []protocol.Diagnostic{constValueDiagnostic(cv, fmt.Sprintf("value %s overflows %s, range is [%d, %d]", constValueText(cv), ft.TypeName.Name, rng[0], rng[1]))}
+		}
+	case "double":
+		if valueType != "i64" && valueType != "double" {
+			return mismatch()
+		}
+	case "string", "binary", "uuid":
+		if valueType != "string" {
+			return mismatch()
+		}
+	case "list", "set":
+		if valueType != "list" {
+			return mismatch()
+		}
+		values, _ := cv.Value.([]*parser.ConstValue)
+		for _, item := range values {
+			res = append(res, s.checkConstValueTypeIn(ctx, ss, file, ast, resolved.File, resolved.AST, resolved.FieldType.KeyType, item)...)
+		}
+	case "map":
+		if valueType != "map" {
+			return mismatch()
+		}
+		values, _ := cv.Value.([]*parser.ConstValue)
+		for _, pair := range values {
+			if pair == nil || pair.BadNode || pair.TypeName != "pair" {
+				continue
+			}
+			key, _ := pair.Key.(*parser.ConstValue)
+			value, _ := pair.Value.(*parser.ConstValue)
+			res = append(res, s.checkConstValueTypeIn(ctx, ss, file, ast, resolved.File, resolved.AST, resolved.FieldType.KeyType, key)...)
+			res = append(res, s.checkConstValueTypeIn(ctx, ss, file, ast, resolved.File, resolved.AST, resolved.FieldType.ValueType, value)...)
+		}
+	}
+
+	return res
+}
+
+// checkIdentifierConstValue checks reference of enum value or const. Undefined references are reported by
+// checkConstValueExist
+func (s *SemanticAnalysis) checkIdentifierConstValue(ctx context.Context, ss *cache.Snapshot,
+	file uri.URI, ast *parser.Document, ft *parser.FieldType, resolved *codejump.ResolvedType, cv *parser.ConstValue) []protocol.Diagnostic {
+	name, _ := cv.Value.(string)
+
+	expectEnum, ok := resolved.Definition.(*parser.Enum)
+	if !ok {
+		return nil
+	}
+
+	enumFile, enum, _, err := codejump.ResolveEnumValue(ctx, ss, file, ast, name)
+	if err != nil || enum == nil {
+		return nil
+	}
+	if enumFile != resolved.DefinitionFile || enum != expectEnum {
+		return []protocol.Diagnostic{constValueDiagnostic(cv, fmt.Sprintf("expect a value of enum %s but got a value of enum %s",
+			ft.TypeName.Name, enum.Name.Name.Text))}
+	}
+
+	return nil
+}
+
+// checkStructLikeConstValue checks struct, union and exception written as map. keys of map are field names
+func (s *SemanticAnalysis) checkStructLikeConstValue(ctx context.Context, ss *cache.Snapshot,
+	file uri.URI, ast *parser.Document, resolved *codejump.ResolvedType, kind string,
+	name *parser.Identifier, fields []*parser.Field, cv *parser.ConstValue) (res []protocol.Diagnostic) {
+	if cv.TypeName != "map" {
+		return []protocol.Diagnostic{constValueDiagnostic(cv, fmt.Sprintf("expect %s but got %s", name.Name.Text, constValueTypeName(cv)))}
+	}
+
+	values, _ := cv.Value.([]*parser.ConstValue)
+	for _, pair := range values {
+		if pair == nil || pair.BadNode || pair.TypeName != "pair" {
+			continue
+		}
+		key, _ := pair.Key.(*parser.ConstValue)
+		value, _ := pair.Value.(*parser.ConstValue)
+		if key == nil || key.BadNode {
+			continue
+		}
+
+		literal, ok := key.Value.(*parser.Literal)
+		if key.TypeName != "string" || !ok || literal.Value == nil {
+			res = append(res, constValueDiagnostic(key, fmt.Sprintf("expect field name of %s %s but got %s", kind, name.Name.Text, constValueTypeName(key))))
+			continue
+		}
+
+		var field *parser.Field
+		for i := range fields {
+			if fields[i].BadNode || fields[i].Identifier == nil || fields[i].Identifier.Name == nil {
+				continue
+			}
+			if fields[i].Identifier.Name.Text == literal.Value.Text {
+				field = fields[i]
+				break
+			}
+		}
+		if field == nil {
+			res = append(res, constValueDiagnostic(key, fmt.Sprintf("unknown field '%s' of %s %s", literal.Value.Text, kind, name.Name.Text)))
+			continue
+		}
+
+		res = append(res, s.checkConstValueTypeIn(ctx, ss, file, ast, resolved.DefinitionFile, resolved.DefinitionAST, field.FieldType, value)...)
+	}
+
+	return res
+}
+
+func enumHasValue(enum *parser.Enum, v int64) bool {
+	for _, value := range enum.Values {
+		if !value.BadNode && value.Value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// constValueTypeName returns type of const value. TypeName of const value can be: list, map, pair, string, identifier, i64, double.
+// true and false are bool
+func constValueTypeName(cv *parser.ConstValue) string {
+	if cv.TypeName == "identifier" && (cv.Value == "true" || cv.Value == "false") {
+		return "bool"
+	}
+	return cv.TypeName
+}
+
+func constValueText(cv *parser.ConstValue) string {
+	if cv.ValueInText != "" {
+		return cv.ValueInText
+	}
+	return fmt.Sprintf("%v", cv.Value)
+}
+
+func constValueDiagnostic(cv *parser.ConstValue, message string) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Range:    lsputils.ASTNodeToRange(cv),
+		Severity: protocol.DiagnosticSeverityError,
+		Source:   "thrift-ls",
+		Message:  message,
+	}
+}
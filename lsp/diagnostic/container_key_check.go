@@ -0,0 +1,225 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/format"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// ContainerKeyCheck reports map keys and set elements which are struct-like, container, double or
+// binary. They are valid thrift but some generators reject or mis-handle them
+type ContainerKeyCheck struct {
+}
+
+func (c *ContainerKeyCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	res := make(DiagnosticResult)
+	for _, file := range changeFiles {
+		items, err := c.diagnostic(ctx, ss, file)
+		if err != nil {
+			return nil, err
+		}
+		res[file] = items
+	}
+
+	return res, nil
+}
+
+func (c *ContainerKeyCheck) Name() string {
+	return "ContainerKeyCheck"
+}
+
+func (c *ContainerKeyCheck) diagnostic(ctx context.Context, ss *cache.Snapshot, file uri.URI) ([]protocol.Diagnostic, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	opts := OptionsFromContext(ctx)
+	languages := targetLanguages(pf.AST(), opts.TargetLanguages)
+
+	var ret []protocol.Diagnostic
+	walkFieldTypes(pf.AST(), func(ft *parser.FieldType) {
+		var key *parser.FieldType
+		var position string
+		switch ft.TypeName.Name {
+		case "map":
+			key, position = ft.KeyType, "map key"
+		case "set":
+			key, position = ft.KeyType, "set element"
+		default:
+			return
+		}
+		if key == nil || key.BadNode || key.TypeName == nil {
+			return
+		}
+
+		resolved, err := codejump.ResolveFieldType(ctx, ss, file, pf.AST(), key)
+		// types which can't be resolved, like types of missing includes, are reported by other checks
+		if err != nil {
+			return
+		}
+		kind := containerKeyKind(resolved)
+		if kind == "" {
+			return
+		}
+
+		affected := unsupportedKeyLanguages[kind]
+		if len(languages) > 0 {
+			affected = intersect(affected, languages)
+		}
+		sev, affected := containerKeySeverity(opts.ContainerKey, affected)
+		if len(affected) == 0 {
+			return
+		}
+
+		subject := format.MustFormatFieldType(key)
+		if subject != kind {
+			subject = fmt.Sprintf("%s type %s", kind, subject)
+		}
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(key),
+			Severity: sev,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("%s as %s is not supported well by %s", subject, position, strings.Join(affected, ", ")),
+		})
+	})
+
+	return ret, nil
+}
+
+// containerKeyKind returns kind of resolved key type which may break generators. It returns empty
+// string for valid key types and unresolved types
+func containerKeyKind(resolved *codejump.ResolvedType) string {
+	if resolved == nil {
+		return ""
+	}
+
+	switch resolved.Definition.(type) {
+	case *parser.Struct:
+		return "struct"
+	case *parser.Union:
+		return "union"
+	case *parser.Exception:
+		return "exception"
+	case *parser.Enum:
+		return ""
+	}
+
+	switch name := resolved.FieldType.TypeName.Name; name {
+	case "list", "set", "map":
+		return "container"
+	case "double", "binary":
+		return name
+	}
+
+	return ""
+}
+
+// walkFieldTypes calls fn for every field type in ast, including key and value types of containers
+func walkFieldTypes(ast *parser.Document, fn func(ft *parser.FieldType)) {
+	var walk func(ft *parser.FieldType)
+	walk = func(ft *parser.FieldType) {
+		if ft == nil || ft.BadNode || ft.TypeName == nil {
+			return
+		}
+		fn(ft)
+		walk(ft.KeyType)
+		walk(ft.ValueType)
+	}
+	walkFields := func(fields []*parser.Field) {
+		for _, field := range fields {
+			if !field.BadNode {
+				walk(field.FieldType)
+			}
+		}
+	}
+
+	for _, node := range ast.Nodes {
+		if node.IsBadNode() {
+			continue
+		}
+		switch n := node.(type) {
+		case *parser.Struct:
+			walkFields(n.Fields)
+		case *parser.Union:
+			walkFields(n.Fields)
+		case *parser.Exception:
+			walkFields(n.Fields)
+		case *parser.Typedef:
+			walk(n.T)
+		case *parser.Const:
+			walk(n.ConstType)
+		case *parser.Service:
+			for _, fn := range n.Functions {
+				if fn.BadNode {
+					continue
+				}
+				walk(fn.FunctionType)
+				walkFields(fn.Arguments)
+				if fn.Throws != nil {
+					walkFields(fn.Throws.Fields)
+				}
+			}
+		}
+	}
+}
+
+// unsupportedKeyLanguages maps key kinds to target languages whose generators reject or mis-handle them
+var unsupportedKeyLanguages = map[string][]string{
+	// structs are mutable or have no hash/compare functions generated
+	"struct":    {LanguageGo, LanguagePython, LanguageCPP, LanguageJavaScript},
+	"union":     {LanguageGo, LanguagePython, LanguageCPP, LanguageJavaScript},
+	"exception": {LanguageGo, LanguagePython, LanguageCPP, LanguageJavaScript},
+	// slices, maps and lists are not hashable
+	"container": {LanguageGo, LanguagePython, LanguageJavaScript},
+	// floating point keys are compared inexactly
+	"double": {LanguageGo, LanguageJava, LanguagePython, LanguageCPP, LanguageJavaScript},
+	// byte slices and buffers are not comparable by content
+	"binary": {LanguageGo, LanguageJava, LanguageJavaScript},
+}
+
+// containerKeySeverity returns the most severe level of languages and languages not ignored.
+// Level of each language defaults to "warning"
+func containerKeySeverity(levels map[string]string, languages []string) (protocol.DiagnosticSeverity, []string) {
+	var res protocol.DiagnosticSeverity
+	var reported []string
+	for _, lang := range languages {
+		sev, ok := severity(levels[lang], protocol.DiagnosticSeverityWarning)
+		if !ok {
+			continue
+		}
+		reported = append(reported, lang)
+		// smaller value is more severe
+		if res == 0 || sev < res {
+			res = sev
+		}
+	}
+
+	return res, reported
+}
+
+func intersect(a, b []string) []string {
+	var res []string
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				res = append(res, x)
+				break
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,109 @@
+package diagnostic
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_ContainerKeyCheck_Diagnostic(t *testing.T) {
+	base := `struct Point {
+  1: i32 x
+}
+typedef Point Key
+enum Color {
+  RED
+}
+exception Err {
+  1: string msg
+}
+typedef Err ErrAlias
+`
+	user := `include "base.thrift"
+include "missing.thrift"
+namespace go user
+namespace java user
+
+struct User {
+  1: map<base.Key, string> points
+  2: set<double> scores
+  3: map<binary, i32> blobs
+  4: set<list<i32>> groups
+  5: map<base.Color, string> colors
+  6: map<string, set<double>> nested
+  7: set<base.Err> errors
+  8: map<base.ErrAlias, i32> counts
+  9: map<missing.T, i32> unresolved
+}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/base.thrift",
+			Version: 0,
+			Content: []byte(base),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(user),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type diag struct {
+		line     uint32
+		severity protocol.DiagnosticSeverity
+		message  string
+	}
+	tests := []struct {
+		name string
+		opts *Options
+		want []diag
+	}{
+		{
+			name: "default severity",
+			opts: &Options{},
+			want: []diag{
+				{line: 6, severity: protocol.DiagnosticSeverityWarning, message: "struct type base.Key as map key is not supported well by go"},
+				{line: 7, severity: protocol.DiagnosticSeverityWarning, message: "double as set element is not supported well by go, java"},
+				{line: 8, severity: protocol.DiagnosticSeverityWarning, message: "binary as map key is not supported well by go, java"},
+				{line: 9, severity: protocol.DiagnosticSeverityWarning, message: "container type list<i32> as set element is not supported well by go"},
+				{line: 11, severity: protocol.DiagnosticSeverityWarning, message: "double as set element is not supported well by go, java"},
+				{line: 12, severity: protocol.DiagnosticSeverityWarning, message: "exception type base.Err as set element is not supported well by go"},
+				{line: 13, severity: protocol.DiagnosticSeverityWarning, message: "exception type base.ErrAlias as map key is not supported well by go"},
+			},
+		},
+		{
+			name: "per language severity",
+			opts: &Options{ContainerKey: map[string]string{"go": SeverityIgnore, "java": SeverityError}},
+			want: []diag{
+				{line: 7, severity: protocol.DiagnosticSeverityError, message: "double as set element is not supported well by java"},
+				{line: 8, severity: protocol.DiagnosticSeverityError, message: "binary as map key is not supported well by java"},
+				{line: 11, severity: protocol.DiagnosticSeverityError, message: "double as set element is not supported well by java"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ContainerKeyCheck{}
+			res, err := c.Diagnostic(WithOptions(context.TODO(), tt.opts), ss, []uri.URI{"file:///tmp/user.thrift"})
+			assert.NoError(t, err)
+
+			items := res["file:///tmp/user.thrift"]
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].Range.Start.Line < items[j].Range.Start.Line
+			})
+			got := make([]diag, 0, len(items))
+			for _, item := range items {
+				got = append(got, diag{line: item.Range.Start.Line, severity: item.Severity, message: item.Message})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package diagnostic
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/joyme123/protocol"
 	"github.com/joyme123/thrift-ls/lsp/cache"
@@ -22,7 +24,15 @@ func (c *CycleCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeF
 	}
 	cyclePairs := cycleDetect(&includesMap)
 
-	return cycleToDiagnosticItems(cyclePairs), nil
+	// diagnostics of a file are published as a whole, so results of files not changed would
+	// replace their other diagnostics
+	res := make(DiagnosticResult)
+	items := cycleToDiagnosticItems(cyclePairs)
+	for _, file := range changeFiles {
+		res[file] = items[file]
+	}
+
+	return res, nil
 }
 
 func (c *CycleCheck) Name() string {
@@ -39,11 +49,21 @@ func cycleToDiagnosticItems(pairs []CyclePair) DiagnosticResult {
 }
 
 func cyclePairToDiagnostic(pair CyclePair) protocol.Diagnostic {
+	dir := filepath.Dir(pair.file.Filename())
+	names := make([]string, 0, len(pair.path))
+	for _, file := range pair.path {
+		name, err := filepath.Rel(dir, file.Filename())
+		if err != nil {
+			name = file.Filename()
+		}
+		names = append(names, filepath.ToSlash(name))
+	}
+
 	res := protocol.Diagnostic{
 		Range:    lsputils.ASTNodeToRange(pair.include.include),
 		Severity: protocol.DiagnosticSeverityWarning,
 		Source:   "thrift-ls",
-		Message:  fmt.Sprintf("cycle dependency in %s", pair.include.file),
+		Message:  fmt.Sprintf("cycle dependency: %s", strings.Join(names, " -> ")),
 	}
 	return res
 }
@@ -56,27 +76,62 @@ type Include struct {
 type CyclePair struct {
 	file    uri.URI
 	include Include
+	// path is the shortest include cycle which starts and ends with file
+	path []uri.URI
 }
 
+// cycleDetect finds includes which are part of a cycle. Each include is reported with the
+// shortest cycle going through it. Self includes are reported by IncludeCheck
 func cycleDetect(includesMap *map[uri.URI][]Include) []CyclePair {
 	cyclePairs := make([]CyclePair, 0)
 
-	for uri, includes := range *includesMap {
+	for file, includes := range *includesMap {
 		for _, incI := range includes {
-			for _, incJ := range (*includesMap)[incI.file] {
-				if uri == incJ.file {
-					cyclePairs = append(cyclePairs, CyclePair{
-						file:    uri,
-						include: incI,
-					})
-				}
+			if incI.file == file {
+				continue
 			}
+			path := shortestIncludePath(includesMap, incI.file, file)
+			if path == nil {
+				continue
+			}
+			cyclePairs = append(cyclePairs, CyclePair{
+				file:    file,
+				include: incI,
+				path:    append([]uri.URI{file}, path...),
+			})
 		}
 	}
 
 	return cyclePairs
 }
 
+// shortestIncludePath returns files on the shortest include path from `from` to `to`, both included.
+// It returns nil if `to` can't be reached
+func shortestIncludePath(includesMap *map[uri.URI][]Include, from, to uri.URI) []uri.URI {
+	prev := map[uri.URI]uri.URI{from: ""}
+	queue := []uri.URI{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == to {
+			var path []uri.URI
+			for file := to; file != ""; file = prev[file] {
+				path = append([]uri.URI{file}, path...)
+			}
+			return path
+		}
+		for _, inc := range (*includesMap)[cur] {
+			if _, ok := prev[inc.file]; ok {
+				continue
+			}
+			prev[inc.file] = cur
+			queue = append(queue, inc.file)
+		}
+	}
+
+	return nil
+}
+
 func getIncludes(ctx context.Context, ss *cache.Snapshot, file uri.URI, includesMap *map[uri.URI][]Include) error {
 	pf, err := ss.Parse(ctx, file)
 	if err != nil {
@@ -22,6 +22,15 @@ func Test_cycleDetect(t *testing.T) {
 		"/goods.thrift":   {Include{file: "/user.thrift"}},
 		"/address.thrift": {Include{file: "/user.thrift"}},
 	}
+	longCycleMap := map[uri.URI][]Include{
+		"/a.thrift": {Include{file: "/b.thrift"}},
+		"/b.thrift": {Include{file: "/c.thrift"}},
+		"/c.thrift": {Include{file: "/a.thrift"}},
+		"/d.thrift": {Include{file: "/a.thrift"}},
+	}
+	selfIncludeMap := map[uri.URI][]Include{
+		"/a.thrift": {Include{file: "/a.thrift"}},
+	}
 
 	type args struct {
 		includesMap *map[uri.URI][]Include
@@ -42,21 +51,55 @@ func Test_cycleDetect(t *testing.T) {
 					include: Include{
 						file: "/goods.thrift",
 					},
+					path: []uri.URI{"/user.thrift", "/goods.thrift", "/user.thrift"},
 				},
 				{
 					file:    "/goods.thrift",
 					include: Include{file: "/user.thrift"},
+					path:    []uri.URI{"/goods.thrift", "/user.thrift", "/goods.thrift"},
 				},
 				{
 					file:    "/user.thrift",
 					include: Include{file: "/address.thrift"},
+					path:    []uri.URI{"/user.thrift", "/address.thrift", "/user.thrift"},
 				},
 				{
 					file:    "/address.thrift",
 					include: Include{file: "/user.thrift"},
+					path:    []uri.URI{"/address.thrift", "/user.thrift", "/address.thrift"},
 				},
 			},
 		},
+		{
+			name: "long cycle",
+			args: args{
+				includesMap: &longCycleMap,
+			},
+			want: []CyclePair{
+				{
+					file:    "/a.thrift",
+					include: Include{file: "/b.thrift"},
+					path:    []uri.URI{"/a.thrift", "/b.thrift", "/c.thrift", "/a.thrift"},
+				},
+				{
+					file:    "/b.thrift",
+					include: Include{file: "/c.thrift"},
+					path:    []uri.URI{"/b.thrift", "/c.thrift", "/a.thrift", "/b.thrift"},
+				},
+				{
+					file:    "/c.thrift",
+					include: Include{file: "/a.thrift"},
+					path:    []uri.URI{"/c.thrift", "/a.thrift", "/b.thrift", "/c.thrift"},
+				},
+			},
+		},
+		{
+			name: "self include",
+			args: args{
+				includesMap: &selfIncludeMap,
+			},
+			want: []CyclePair{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -474,6 +517,39 @@ include "./test/address.thrift"`
 	}
 }
 
+func Test_CycleCheck_Diagnostic(t *testing.T) {
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/a.thrift",
+			Version: 0,
+			Content: []byte(`include "b.thrift"`),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/b.thrift",
+			Version: 0,
+			Content: []byte(`include "c.thrift"`),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/c.thrift",
+			Version: 0,
+			Content: []byte(`include "a.thrift"`),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	c := &CycleCheck{}
+	res, err := c.Diagnostic(context.TODO(), ss, []uri.URI{"file:///tmp/a.thrift"})
+	assert.NoError(t, err)
+
+	// only changed files are reported, diagnostics of other files in cycle are kept
+	assert.Len(t, res, 1)
+	if assert.Len(t, res["file:///tmp/a.thrift"], 1) {
+		assert.Equal(t, "cycle dependency: a.thrift -> b.thrift -> c.thrift -> a.thrift", res["file:///tmp/a.thrift"][0].Message)
+	}
+}
+
 func buildSnapshotForTest(files []*cache.FileChange) *cache.Snapshot {
 	store := &memoize.Store{}
 	c := cache.New(store)
@@ -18,6 +18,13 @@ func init() {
 		&Parse{},
 		&FieldIDCheck{},
 		&SemanticAnalysis{},
+		&EnumCheck{},
+		&ServiceCheck{},
+		&TypedefCheck{},
+		&UnusedCheck{},
+		&IncludeCheck{},
+		&ReservedWordCheck{},
+		&ContainerKeyCheck{},
 	}
 }
 
@@ -27,13 +34,17 @@ type Interface interface {
 }
 
 type Diagnostic struct {
+	opts *Options
 }
 
-func NewDiagnostic() Interface {
-	return &Diagnostic{}
+func NewDiagnostic(opts *Options) Interface {
+	return &Diagnostic{
+		opts: opts,
+	}
 }
 
 func (d *Diagnostic) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	ctx = WithOptions(ctx, d.opts)
 	res := make(DiagnosticResult)
 	var errs []error
 	for _, impl := range registry {
@@ -0,0 +1,149 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// EnumCheck checks enum members: duplicate names and values, int32 range, negative values and empty enums.
+// implicit values are numbered the same as Apache compiler, see parser.AssignEnumValues
+type EnumCheck struct {
+}
+
+func (c *EnumCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	res := make(DiagnosticResult)
+	for _, file := range changeFiles {
+		items, err := c.diagnostic(ctx, ss, file)
+		if err != nil {
+			return nil, err
+		}
+		res[file] = items
+	}
+
+	return res, nil
+}
+
+func (c *EnumCheck) Name() string {
+	return "EnumCheck"
+}
+
+func (c *EnumCheck) diagnostic(ctx context.Context, ss *cache.Snapshot, file uri.URI) ([]protocol.Diagnostic, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	opts := OptionsFromContext(ctx)
+
+	var ret []protocol.Diagnostic
+	for _, enum := range pf.AST().Enums {
+		if enum.BadNode || enum.Name == nil || enum.Name.Name == nil {
+			continue
+		}
+		ret = append(ret, c.checkEnum(opts, enum)...)
+	}
+
+	return ret, nil
+}
+
+func (c *EnumCheck) checkEnum(opts *Options, enum *parser.Enum) (ret []protocol.Diagnostic) {
+	values := make([]*parser.EnumValue, 0, len(enum.Values))
+	for _, v := range enum.Values {
+		if v.BadNode || v.Name == nil || v.Name.Name == nil {
+			continue
+		}
+		values = append(values, v)
+	}
+
+	if len(values) == 0 {
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(enum.Name.Name),
+			Severity: protocol.DiagnosticSeverityWarning,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("enum %s has no values", enum.Name.Name.Text),
+		})
+		return ret
+	}
+
+	names := make(map[string][]*parser.EnumValue)
+	numbers := make(map[int64][]*parser.EnumValue)
+	for _, v := range values {
+		names[v.Name.Name.Text] = append(names[v.Name.Name.Text], v)
+		numbers[v.Value] = append(numbers[v.Value], v)
+	}
+
+	negativeSeverity, reportNegative := severity(opts.NegativeEnumValue, protocol.DiagnosticSeverityWarning)
+
+	for _, v := range values {
+		if len(names[v.Name.Name.Text]) > 1 {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    lsputils.ASTNodeToRange(v.Name.Name),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("duplicate enum member name %s", v.Name.Name.Text),
+			})
+		}
+
+		if others := otherEnumValueNames(numbers[v.Value], v); len(others) > 0 {
+			message := fmt.Sprintf("duplicate enum value %d, also used by %s", v.Value, strings.Join(others, ", "))
+			if v.ValueNode == nil {
+				message = fmt.Sprintf("implicit value %d of %s collides with %s", v.Value, v.Name.Name.Text, strings.Join(others, ", "))
+			}
+			ret = append(ret, protocol.Diagnostic{
+				Range:    enumValueRange(v),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  message,
+			})
+		}
+
+		if v.Value < math.MinInt32 || v.Value > math.MaxInt32 {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    enumValueRange(v),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("enum value %d is out of int32 range [%d, %d]", v.Value, math.MinInt32, math.MaxInt32),
+			})
+		} else if v.Value < 0 && reportNegative {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    enumValueRange(v),
+				Severity: negativeSeverity,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("negative enum value %d", v.Value),
+			})
+		}
+	}
+
+	return ret
+}
+
+func otherEnumValueNames(values []*parser.EnumValue, self *parser.EnumValue) []string {
+	var names []string
+	for _, v := range values {
+		if v != self {
+			names = append(names, v.Name.Name.Text)
+		}
+	}
+	return names
+}
+
+// enumValueRange returns range of explicit value, or range of name if value is implicit
+func enumValueRange(v *parser.EnumValue) protocol.Range {
+	if v.ValueNode != nil {
+		return lsputils.ASTNodeToRange(v.ValueNode)
+	}
+	return lsputils.ASTNodeToRange(v.Name.Name)
+}
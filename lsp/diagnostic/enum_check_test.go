@@ -0,0 +1,104 @@
+package diagnostic
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_EnumCheck_Diagnostic(t *testing.T) {
+	file1 := `enum Status {
+  OK,
+  Failed = 0,
+  Unknown = 1,
+  OK = 3
+}
+
+enum Empty {}
+
+enum Range {
+  Min = -1,
+  Max = 2147483648
+}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(file1),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type diag struct {
+		line     uint32
+		severity protocol.DiagnosticSeverity
+		message  string
+	}
+	tests := []struct {
+		name string
+		opts *Options
+		want []diag
+	}{
+		{
+			name: "default options",
+			opts: &Options{},
+			want: []diag{
+				{line: 1, severity: protocol.DiagnosticSeverityError, message: "duplicate enum member name OK"},
+				{line: 1, severity: protocol.DiagnosticSeverityError, message: "implicit value 0 of OK collides with Failed"},
+				{line: 2, severity: protocol.DiagnosticSeverityError, message: "duplicate enum value 0, also used by OK"},
+				{line: 4, severity: protocol.DiagnosticSeverityError, message: "duplicate enum member name OK"},
+				{line: 7, severity: protocol.DiagnosticSeverityWarning, message: "enum Empty has no values"},
+				{line: 10, severity: protocol.DiagnosticSeverityWarning, message: "negative enum value -1"},
+				{line: 11, severity: protocol.DiagnosticSeverityError, message: "enum value 2147483648 is out of int32 range [-2147483648, 2147483647]"},
+			},
+		},
+		{
+			name: "negative value is error",
+			opts: &Options{NegativeEnumValue: SeverityError},
+			want: []diag{
+				{line: 1, severity: protocol.DiagnosticSeverityError, message: "duplicate enum member name OK"},
+				{line: 1, severity: protocol.DiagnosticSeverityError, message: "implicit value 0 of OK collides with Failed"},
+				{line: 2, severity: protocol.DiagnosticSeverityError, message: "duplicate enum value 0, also used by OK"},
+				{line: 4, severity: protocol.DiagnosticSeverityError, message: "duplicate enum member name OK"},
+				{line: 7, severity: protocol.DiagnosticSeverityWarning, message: "enum Empty has no values"},
+				{line: 10, severity: protocol.DiagnosticSeverityError, message: "negative enum value -1"},
+				{line: 11, severity: protocol.DiagnosticSeverityError, message: "enum value 2147483648 is out of int32 range [-2147483648, 2147483647]"},
+			},
+		},
+		{
+			name: "negative value is ignored",
+			opts: &Options{NegativeEnumValue: SeverityIgnore},
+			want: []diag{
+				{line: 1, severity: protocol.DiagnosticSeverityError, message: "duplicate enum member name OK"},
+				{line: 1, severity: protocol.DiagnosticSeverityError, message: "implicit value 0 of OK collides with Failed"},
+				{line: 2, severity: protocol.DiagnosticSeverityError, message: "duplicate enum value 0, also used by OK"},
+				{line: 4, severity: protocol.DiagnosticSeverityError, message: "duplicate enum member name OK"},
+				{line: 7, severity: protocol.DiagnosticSeverityWarning, message: "enum Empty has no values"},
+				{line: 11, severity: protocol.DiagnosticSeverityError, message: "enum value 2147483648 is out of int32 range [-2147483648, 2147483647]"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &EnumCheck{}
+			res, err := c.Diagnostic(WithOptions(context.TODO(), tt.opts), ss, []uri.URI{"file:///tmp/user.thrift"})
+			assert.NoError(t, err)
+
+			items := res["file:///tmp/user.thrift"]
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].Range.Start.Line < items[j].Range.Start.Line
+			})
+			got := make([]diag, 0, len(items))
+			for _, item := range items {
+				got = append(got, diag{line: item.Range.Start.Line, severity: item.Severity, message: item.Message})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
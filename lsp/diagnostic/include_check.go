@@ -0,0 +1,190 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"github.com/joyme123/thrift-ls/utils"
+	"go.lsp.dev/uri"
+)
+
+// IncludeCheck checks include paths: missing files, case mismatch, duplicate includes,
+// self include, conflicting prefixes and files outside of workspace
+type IncludeCheck struct {
+}
+
+func (c *IncludeCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	res := make(DiagnosticResult)
+	for _, file := range changeFiles {
+		items, err := c.diagnostic(ctx, ss, file)
+		if err != nil {
+			return nil, err
+		}
+		res[file] = items
+	}
+
+	return res, nil
+}
+
+func (c *IncludeCheck) Name() string {
+	return "IncludeCheck"
+}
+
+func (c *IncludeCheck) diagnostic(ctx context.Context, ss *cache.Snapshot, file uri.URI) ([]protocol.Diagnostic, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	var ret []protocol.Diagnostic
+	newDiagnostic := func(inc *parser.Include, severity protocol.DiagnosticSeverity, message string) {
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(inc),
+			Severity: severity,
+			Source:   "thrift-ls",
+			Message:  message,
+		})
+	}
+
+	included := make(map[uri.URI]*parser.Include)
+	prefixes := make(map[string]*parser.Include)
+	for _, inc := range pf.AST().Includes {
+		if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+			continue
+		}
+		includePath := inc.Path.Value.Text
+		includeURI := lsputils.IncludeURI(file, includePath)
+
+		if includeURI == file {
+			newDiagnostic(inc, protocol.DiagnosticSeverityError, "file can't include itself")
+			continue
+		}
+
+		if prev, ok := included[includeURI]; ok {
+			newDiagnostic(inc, protocol.DiagnosticSeverityWarning,
+				fmt.Sprintf("duplicate include of %q, already included at line %d", includePath, prev.Pos().Line))
+			continue
+		}
+		included[includeURI] = inc
+
+		if prev, ok := prefixes[inc.Name()]; ok {
+			newDiagnostic(inc, protocol.DiagnosticSeverityError,
+				fmt.Sprintf("include prefix %s is already used by %q", inc.Name(), prev.Path.Value.Text))
+		} else {
+			prefixes[inc.Name()] = inc
+		}
+
+		if !ss.ContainsFile(includeURI) {
+			newDiagnostic(inc, protocol.DiagnosticSeverityWarning, "include file is outside of workspace")
+		}
+
+		fh, err := ss.ReadFile(ctx, includeURI)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fh.Content(); err != nil {
+			message := fmt.Sprintf("include file %q not found", includePath)
+			if name := similarFileName(includeURI); name != "" {
+				suggestion := path.Join(path.Dir(includePath), name)
+				message = fmt.Sprintf("%s, did you mean %q?", message, suggestion)
+			}
+			newDiagnostic(inc, protocol.DiagnosticSeverityError, message)
+			continue
+		}
+
+		// file is opened successfully on case-insensitive filesystem
+		if name := caseMismatchFileName(includeURI); name != "" {
+			newDiagnostic(inc, protocol.DiagnosticSeverityWarning,
+				fmt.Sprintf("include path %q doesn't match file name %s in case", includePath, name))
+		}
+	}
+
+	return ret, nil
+}
+
+// flaggedIncludes returns self includes, duplicate includes and includes of missing files, which
+// are reported by IncludeCheck
+func flaggedIncludes(ctx context.Context, ss *cache.Snapshot, file uri.URI, includes []*parser.Include) map[*parser.Include]struct{} {
+	res := make(map[*parser.Include]struct{})
+	included := make(map[uri.URI]struct{})
+	for _, inc := range includes {
+		if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+			continue
+		}
+		includeURI := lsputils.IncludeURI(file, inc.Path.Value.Text)
+		if _, ok := included[includeURI]; ok || includeURI == file {
+			res[inc] = struct{}{}
+			continue
+		}
+		included[includeURI] = struct{}{}
+
+		fh, err := ss.ReadFile(ctx, includeURI)
+		if err != nil {
+			res[inc] = struct{}{}
+			continue
+		}
+		if _, err := fh.Content(); err != nil {
+			res[inc] = struct{}{}
+		}
+	}
+
+	return res
+}
+
+// similarFileName returns the most similar thrift file name in directory of file
+func similarFileName(file uri.URI) string {
+	entries, err := os.ReadDir(filepath.Dir(file.Filename()))
+	if err != nil {
+		return ""
+	}
+
+	name := filepath.Base(file.Filename())
+	res, minDistance := "", len(name)/3+1
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != filepath.Ext(name) {
+			continue
+		}
+		if strings.EqualFold(entry.Name(), name) {
+			return entry.Name()
+		}
+		if distance := utils.EditDistance(entry.Name(), name); distance < minDistance {
+			res, minDistance = entry.Name(), distance
+		}
+	}
+
+	return res
+}
+
+// caseMismatchFileName returns actual file name if it differs from name of file in case
+func caseMismatchFileName(file uri.URI) string {
+	entries, err := os.ReadDir(filepath.Dir(file.Filename()))
+	if err != nil {
+		return ""
+	}
+
+	name := filepath.Base(file.Filename())
+	res := ""
+	for _, entry := range entries {
+		if entry.Name() == name {
+			return ""
+		}
+		if strings.EqualFold(entry.Name(), name) {
+			res = entry.Name()
+		}
+	}
+
+	return res
+}
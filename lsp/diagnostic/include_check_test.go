@@ -0,0 +1,85 @@
+package diagnostic
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/memoize"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_IncludeCheck_Diagnostic(t *testing.T) {
+	dir := t.TempDir()
+	workspace := filepath.Join(dir, "workspace")
+	for _, name := range []string{"base.thrift", "a/common.thrift", "b/common.thrift"} {
+		path := filepath.Join(workspace, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, []byte(""), os.ModePerm))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "outside.thrift"), []byte(""), os.ModePerm))
+
+	main := `include "base.thrift"
+include "./base.thrift"
+include "bse.thrift"
+include "BASE.thrift"
+include "main.thrift"
+include "a/common.thrift"
+include "b/common.thrift"
+include "../outside.thrift"
+include "missing.thrift"
+`
+	file := uri.File(filepath.Join(workspace, "main.thrift"))
+	store := &memoize.Store{}
+	fs := cache.NewOverlayFS(cache.New(store))
+	fs.Update(context.TODO(), []*cache.FileChange{
+		{
+			URI:     file,
+			Version: 0,
+			Content: []byte(main),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+	view := cache.NewView("test", uri.File(workspace), fs, store)
+	ss := cache.NewSnapshot(view, store)
+
+	type diag struct {
+		line    uint32
+		message string
+	}
+	want := []diag{
+		{line: 1, message: `duplicate include of "./base.thrift", already included at line 1`},
+		{line: 2, message: `include file "bse.thrift" not found, did you mean "base.thrift"?`},
+		{line: 4, message: "file can't include itself"},
+		{line: 6, message: `include prefix common is already used by "a/common.thrift"`},
+		{line: 7, message: "include file is outside of workspace"},
+		{line: 8, message: `include file "missing.thrift" not found`},
+	}
+	// case insensitive filesystem opens BASE.thrift successfully
+	if _, err := os.Stat(filepath.Join(workspace, "BASE.thrift")); err == nil {
+		want = append(want, diag{line: 3, message: `include path "BASE.thrift" doesn't match file name base.thrift in case`})
+	} else {
+		want = append(want, diag{line: 3, message: `include file "BASE.thrift" not found, did you mean "base.thrift"?`})
+	}
+	sort.SliceStable(want, func(i, j int) bool {
+		return want[i].line < want[j].line
+	})
+
+	c := &IncludeCheck{}
+	res, err := c.Diagnostic(context.TODO(), ss, []uri.URI{file})
+	assert.NoError(t, err)
+
+	items := res[file]
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Range.Start.Line < items[j].Range.Start.Line
+	})
+	got := make([]diag, 0, len(items))
+	for _, item := range items {
+		got = append(got, diag{line: item.Range.Start.Line, message: item.Message})
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,64 @@
+package diagnostic
+
+import (
+	"github.com/joyme123/thrift-ls/parser"
+)
+
+// target languages of generators
+const (
+	LanguageGo         = "go"
+	LanguageJava       = "java"
+	LanguagePython     = "python"
+	LanguageCPP        = "cpp"
+	LanguageJavaScript = "js"
+)
+
+var allLanguages = []string{LanguageGo, LanguageJava, LanguagePython, LanguageCPP, LanguageJavaScript}
+
+// targetLanguages returns languages of namespace scopes in ast and configured languages,
+// in order of allLanguages. Namespace scope `*` means all languages
+func targetLanguages(ast *parser.Document, configured []string) []string {
+	set := make(map[string]struct{})
+	add := func(scope string) {
+		if scope == "*" {
+			for _, lang := range allLanguages {
+				set[lang] = struct{}{}
+			}
+			return
+		}
+		if lang, ok := scopeLanguages[scope]; ok {
+			set[lang] = struct{}{}
+		}
+	}
+
+	for _, ns := range ast.Namespaces {
+		if ns.BadNode || ns.Language == nil || ns.Language.Name == nil {
+			continue
+		}
+		add(ns.Language.Name.Text)
+	}
+	for _, lang := range configured {
+		add(lang)
+	}
+
+	res := make([]string, 0, len(set))
+	for _, lang := range allLanguages {
+		if _, ok := set[lang]; ok {
+			res = append(res, lang)
+		}
+	}
+
+	return res
+}
+
+// scopeLanguages maps namespace scopes to target languages
+var scopeLanguages = map[string]string{
+	"go":         LanguageGo,
+	"java":       LanguageJava,
+	"py":         LanguagePython,
+	"py.twisted": LanguagePython,
+	"python":     LanguagePython,
+	"cpp":        LanguageCPP,
+	"js":         LanguageJavaScript,
+	"nodejs":     LanguageJavaScript,
+}
@@ -0,0 +1,71 @@
+package diagnostic
+
+import (
+	"context"
+
+	"github.com/joyme123/protocol"
+)
+
+// severity levels of configurable diagnostics
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityHint    = "hint"
+	SeverityIgnore  = "ignore"
+)
+
+// Options configures diagnostics. Zero value means default behavior
+type Options struct {
+	// NegativeEnumValue is severity of negative enum values.
+	// Options: "error", "warning", "info", "hint", "ignore". Default is "warning"
+	NegativeEnumValue string `json:"negativeEnumValue" yaml:"negativeEnumValue"`
+	// UnusedInclude is severity of includes never referenced. Default is "hint"
+	UnusedInclude string `json:"unusedInclude" yaml:"unusedInclude"`
+	// UnusedDefinition is severity of structs, unions, exceptions, enums, typedefs and consts
+	// which can't be reached from any service. It is disabled unless a severity is set
+	UnusedDefinition string `json:"unusedDefinition" yaml:"unusedDefinition"`
+	// ReservedWord is severity of names which are reserved words of target languages. Default is "warning"
+	ReservedWord string `json:"reservedWord" yaml:"reservedWord"`
+	// TargetLanguages are checked for reserved words besides namespace scopes of each file.
+	// Options: "go", "java", "python", "cpp", "js"
+	TargetLanguages []string `json:"targetLanguages" yaml:"targetLanguages"`
+	// ContainerKey is severity of map keys and set elements not supported well by generators,
+	// keyed by target language. Severity of each language defaults to "warning"
+	ContainerKey map[string]string `json:"containerKey" yaml:"containerKey"`
+}
+
+type optionsKey struct{}
+
+// WithOptions returns a context carrying diagnostic options
+func WithOptions(ctx context.Context, opts *Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, opts)
+}
+
+// OptionsFromContext returns diagnostic options in ctx. Default options are returned if not set
+func OptionsFromContext(ctx context.Context) *Options {
+	opts, ok := ctx.Value(optionsKey{}).(*Options)
+	if !ok || opts == nil {
+		return &Options{}
+	}
+	return opts
+}
+
+// severity converts configured level to diagnostic severity. If level is empty or invalid, def is used.
+// It returns false if the diagnostic is ignored
+func severity(level string, def protocol.DiagnosticSeverity) (protocol.DiagnosticSeverity, bool) {
+	switch level {
+	case SeverityError:
+		return protocol.DiagnosticSeverityError, true
+	case SeverityWarning:
+		return protocol.DiagnosticSeverityWarning, true
+	case SeverityInfo:
+		return protocol.DiagnosticSeverityInformation, true
+	case SeverityHint:
+		return protocol.DiagnosticSeverityHint, true
+	case SeverityIgnore:
+		return 0, false
+	}
+
+	return def, true
+}
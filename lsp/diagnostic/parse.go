@@ -2,9 +2,15 @@ package diagnostic
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/joyme123/protocol"
 	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
 	"github.com/joyme123/thrift-ls/parser"
 	"github.com/joyme123/thrift-ls/utils/errors"
 	log "github.com/sirupsen/logrus"
@@ -24,15 +30,27 @@ func (p *Parse) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles
 			errs = append(errs, err)
 			continue
 		}
+		fh, err := ss.ReadFile(ctx, uri)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		content, err := fh.Content()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
 
 		// TODO(jpf): 递归解析
-		for _, err := range parseRes.Errors() {
-			parseErr, ok := err.(parser.ParserError)
-			if !ok {
+		parseErrs := parseRes.Errors()
+		for i, parseErr := range parseErrs {
+			// parser may report errors of inner rule and outer rule at the same position,
+			// e.g. an invalid field and a missing '}' at end of file. The outer one is more accurate
+			if i+1 < len(parseErrs) && sameErrorPos(parseErr, parseErrs[i+1]) {
 				continue
 			}
 			log.Debugf("diagnostic parse err: %v", parseErr)
-			diag := parseErrToDiagnostic(parseErr)
+			diag := parseErrToDiagnostic(uri, parser.StripBOM(content), parseRes.AST(), parseErr)
 			res[uri] = append(res[uri], diag)
 		}
 	}
@@ -48,23 +66,308 @@ func (p *Parse) Name() string {
 	return "Parse"
 }
 
-func parseErrToDiagnostic(err parser.ParserError) protocol.Diagnostic {
-	line, col, _ := err.Pos()
+func sameErrorPos(a, b parser.ParserError) bool {
+	_, _, offsetA := a.Pos()
+	_, _, offsetB := b.Pos()
+	return offsetA == offsetB
+}
+
+// expectations describes parser errors in plain words
+var expectations = map[error]string{
+	parser.RequiredError:          "'required' or 'optional'",
+	parser.InvalidFieldTypeError:  "a field type",
+	parser.InvalidFieldIndexError: "a field id between 1 and 32767",
+
+	parser.InvalidStructIdentifierError:    "a struct name",
+	parser.InvalidStructBlockLCURError:     "'{' to start the struct body",
+	parser.InvalidStructFieldError:         "a field like '1: required string name' or '}'",
+	parser.InvalidUnionIdentifierError:     "a union name",
+	parser.InvalidUnionBlockLCURError:      "'{' to start the union body",
+	parser.InvalidUnionFieldError:          "a field like '1: string name' or '}'",
+	parser.InvalidExceptionIdentifierError: "an exception name",
+	parser.InvalidExceptionBlockLCURError:  "'{' to start the exception body",
+	parser.InvalidExceptionFieldError:      "a field like '1: string message' or '}'",
+
+	parser.InvalidEnumIdentifierError:       "an enum name",
+	parser.InvalidEnumBlockLCURError:        "'{' to start the enum body",
+	parser.InvalidEnumValueError:            "an enum value like 'NAME = 1' or '}'",
+	parser.InvalidEnumValueIntConstantError: "an integer value",
+
+	parser.InvalidTypedefIdentifierError: "a typedef name",
+
+	parser.InvalidConstConstValueError:   "a const value",
+	parser.InvalidConstMissingValueError: "a const value",
+	parser.InvalidConstIdentifierError:   "a const name",
+
+	parser.InvalidServiceIdentifierError:  "a service name",
+	parser.InvalidServiceFunctionError:    "a function like 'string Hello(1: string name)' or '}'",
+	parser.InvalidFunctionIdentifierError: "a function name",
+	parser.InvalidFunctionArgumentError:   "an argument like '1: string name' or ')'",
+
+	parser.InvalidIdentifierError: "an identifier",
+	parser.InvalidLiteral1Error:   "a string literal",
+	parser.InvalidLiteral2Error:   "a string literal",
+
+	parser.InvalidHeaderError:     "include, cpp_include or namespace",
+	parser.InvalidIncludeError:    "a quoted file path like 'include \"base.thrift\"'",
+	parser.InvalidCppIncludeError: "a quoted file path like 'cpp_include \"base.h\"'",
+	parser.InvalidNamespaceError:  "a namespace like 'namespace go example'",
+	parser.InvalidDefinitionError: "a definition: struct, union, exception, enum, service, typedef or const",
+}
+
+// blockKinds are parser errors of missing '}'
+var blockKinds = map[error]string{
+	parser.InvalidStructBlockRCURError:    "struct",
+	parser.InvalidUnionBlockRCURError:     "union",
+	parser.InvalidExceptionBlockRCURError: "exception",
+	parser.InvalidEnumBlockRCURError:      "enum",
+	parser.InvalidServiceBlockRCURError:   "service",
+}
+
+func parseErrToDiagnostic(file uri.URI, content []byte, ast *parser.Document, err parser.ParserError) protocol.Diagnostic {
+	_, _, offset := err.Pos()
+	token, tokenOffset := tokenAt(content, offset)
+
+	start := offsetToPosition(content, tokenOffset)
+	end := start
+	end.Character += uint32(utf8.RuneCountInString(token))
+
 	diag := protocol.Diagnostic{
 		Range: protocol.Range{
-			Start: protocol.Position{
-				Line:      uint32(line - 1),
-				Character: uint32(col - 1),
-			},
-			End: protocol.Position{
-				Line:      uint32(line - 1),
-				Character: uint32(col - 1),
-			},
+			Start: start,
+			End:   end,
 		},
 		Severity: protocol.DiagnosticSeverityError,
 		Source:   "thrift-ls",
-		Message:  err.InnerError().Error(),
+		Message:  parseErrMessage(err.InnerError(), content, token, tokenOffset),
+	}
+
+	if kind, ok := blockKinds[err.InnerError()]; ok {
+		if name, lcur := unclosedBlock(ast, tokenOffset); lcur != nil {
+			diag.Message = fmt.Sprintf("'{' of %s %s is not closed, expected '}' before %s", kind, name, describeToken(token))
+			diag.RelatedInformation = []protocol.DiagnosticRelatedInformation{
+				{
+					Location: protocol.Location{
+						URI:   file,
+						Range: lsputils.ASTNodeToRange(lcur),
+					},
+					Message: fmt.Sprintf("%s %s starts here", kind, name),
+				},
+			}
+		}
 	}
 
 	return diag
 }
+
+var (
+	fieldIDRegexp            = regexp.MustCompile(`^(-?\d+)\s*(\S*)`)
+	fieldWithoutNameRegexp   = regexp.MustCompile(`^-?\d+\s*:\s*(\S+)(\s+\S+)?\s*[,;]?\s*$`)
+	fieldWithoutDefaultRegex = regexp.MustCompile(`=\s*[,;]?\s*$`)
+	fieldWithoutIDRegexp     = regexp.MustCompile(`^[A-Za-z_]`)
+)
+
+// parseErrMessage builds message which names the offending token and what is expected.
+// Common mistakes are detected by looking at the rest of line.
+func parseErrMessage(inner error, content []byte, token string, tokenOffset int) string {
+	line := restOfLine(content, tokenOffset)
+
+	switch inner {
+	case parser.InvalidStructFieldError, parser.InvalidUnionFieldError, parser.InvalidExceptionFieldError,
+		parser.InvalidFunctionArgumentError, parser.InvalidFieldIndexError:
+		if hint := fieldHint(line); hint != "" {
+			return hint
+		}
+	case parser.InvalidServiceFunctionError:
+		if strings.Contains(line, "(") && !strings.Contains(line, ")") {
+			return "expected ')' to close the argument list of function"
+		}
+	case parser.InvalidDefinitionError:
+		if token == ";" || token == "," {
+			return fmt.Sprintf("unexpected '%s', separators are not allowed after a definition", token)
+		}
+		if token == "}" {
+			return "unexpected '}', there is no block to close"
+		}
+	case parser.InvalidLiteral1MissingRightError:
+		return "string literal is not closed, expected a closing '\"'"
+	case parser.InvalidLiteral2MissingRightError:
+		return "string literal is not closed, expected a closing \"'\""
+	case parser.InvalidConstConstValueError, parser.InvalidConstMissingValueError:
+		if token == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, ",") {
+			return "expected a const value after '='"
+		}
+	}
+
+	if kind, ok := blockKinds[inner]; ok {
+		return fmt.Sprintf("unexpected %s, expected '}' to close the %s body", describeToken(token), kind)
+	}
+
+	expected, ok := expectations[inner]
+	if !ok {
+		if inner == nil {
+			return fmt.Sprintf("unexpected %s", describeToken(token))
+		}
+		return fmt.Sprintf("%s, found %s", inner.Error(), describeToken(token))
+	}
+
+	return fmt.Sprintf("unexpected %s, expected %s", describeToken(token), expected)
+}
+
+// fieldHint detects common mistakes of field line like '1 = i32 a' or '1: i32'
+func fieldHint(line string) string {
+	if matches := fieldIDRegexp.FindStringSubmatch(line); matches != nil {
+		id, next := matches[1], matches[2]
+		switch {
+		case next == "":
+			return fmt.Sprintf("expected ':' after field id %s", id)
+		case strings.HasPrefix(next, "="):
+			return fmt.Sprintf("expected ':' after field id %s, found '='", id)
+		case !strings.HasPrefix(next, ":"):
+			return fmt.Sprintf("expected ':' after field id %s, found '%s'", id, next)
+		}
+	}
+	if fieldWithoutDefaultRegex.MatchString(line) {
+		return "expected a default value after '='"
+	}
+	if matches := fieldWithoutNameRegexp.FindStringSubmatch(line); matches != nil {
+		if matches[2] == "" {
+			if matches[1] == "required" || matches[1] == "optional" {
+				return fmt.Sprintf("expected a field type after '%s'", matches[1])
+			}
+			return fmt.Sprintf("expected a field name after type '%s'", matches[1])
+		}
+		if matches[1] == "required" || matches[1] == "optional" {
+			return fmt.Sprintf("expected a field name after type '%s'", strings.TrimSpace(matches[2]))
+		}
+	}
+	if fieldWithoutIDRegexp.MatchString(line) {
+		return fmt.Sprintf("expected a field id, fields are written like '1: %s'", strings.TrimRight(line, ",; \t"))
+	}
+
+	return ""
+}
+
+// unclosedBlock returns the last definition before offset whose '{' is not closed
+func unclosedBlock(ast *parser.Document, offset int) (string, parser.Node) {
+	if ast == nil {
+		return "", nil
+	}
+
+	var name string
+	var lcur parser.Node
+	for _, node := range ast.Nodes {
+		var id *parser.Identifier
+		var l *parser.LCurKeyword
+		var r *parser.RCurKeyword
+		switch n := node.(type) {
+		case *parser.Struct:
+			id, l, r = n.Identifier, n.LCurKeyword, n.RCurKeyword
+		case *parser.Union:
+			id, l, r = n.Name, n.LCurKeyword, n.RCurKeyword
+		case *parser.Exception:
+			id, l, r = n.Name, n.LCurKeyword, n.RCurKeyword
+		case *parser.Enum:
+			id, l, r = n.Name, n.LCurKeyword, n.RCurKeyword
+		case *parser.Service:
+			id, l, r = n.Name, n.LCurKeyword, n.RCurKeyword
+		default:
+			continue
+		}
+		if l == nil || l.BadNode || l.Pos().Offset > offset {
+			continue
+		}
+		// a missing '}' is recovered as a keyword with bad literal
+		if r != nil && !r.BadNode && r.Literal != nil && !r.Literal.BadNode && r.Pos().Offset < offset {
+			continue
+		}
+		lcur = l
+		name = ""
+		if id != nil && id.Name != nil {
+			name = id.Name.Text
+		}
+	}
+
+	return name, lcur
+}
+
+// tokenAt returns token starting from offset. Whitespaces before token are skipped.
+// It returns empty string when the end of file is reached.
+func tokenAt(content []byte, offset int) (string, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	for offset < len(content) {
+		r, size := utf8.DecodeRune(content[offset:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		offset += size
+	}
+	if offset >= len(content) {
+		return "", len(content)
+	}
+
+	r, size := utf8.DecodeRune(content[offset:])
+	switch {
+	case isIdentifierRune(r):
+		end := offset
+		for end < len(content) {
+			r, size := utf8.DecodeRune(content[end:])
+			if !isIdentifierRune(r) && r != '.' {
+				break
+			}
+			end += size
+		}
+		return string(content[offset:end]), offset
+	case r == '"' || r == '\'':
+		end := offset + size
+		for end < len(content) && content[end] != '\n' {
+			if rune(content[end]) == r {
+				end++
+				break
+			}
+			end++
+		}
+		return string(content[offset:end]), offset
+	}
+
+	return string(r), offset
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func describeToken(token string) string {
+	if token == "" {
+		return "end of file"
+	}
+	return fmt.Sprintf("'%s'", token)
+}
+
+func restOfLine(content []byte, offset int) string {
+	if offset >= len(content) {
+		return ""
+	}
+	line := content[offset:]
+	if i := strings.IndexByte(string(line), '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(string(line))
+}
+
+// offsetToPosition converts byte offset to lsp position. Character is counted in runes,
+// same as positions of ast nodes
+func offsetToPosition(content []byte, offset int) protocol.Position {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	prefix := content[:offset]
+	line := strings.Count(string(prefix), "\n")
+	lineStart := strings.LastIndexByte(string(prefix), '\n') + 1
+	return protocol.Position{
+		Line:      uint32(line),
+		Character: uint32(utf8.RuneCount(prefix[lineStart:])),
+	}
+}
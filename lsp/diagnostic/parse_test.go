@@ -0,0 +1,150 @@
+package diagnostic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_Parse_Diagnostic(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []protocol.Diagnostic
+	}{
+		{
+			name:    "field id without colon",
+			content: "struct Test {\n  1 i32 a\n}",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 1, Character: 2}, End: protocol.Position{Line: 1, Character: 3}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "expected ':' after field id 1, found 'i32'",
+				},
+			},
+		},
+		{
+			name:    "equal instead of colon",
+			content: "struct Test {\n  3= i32 a\n}",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 1, Character: 2}, End: protocol.Position{Line: 1, Character: 3}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "expected ':' after field id 3, found '='",
+				},
+			},
+		},
+		{
+			name:    "field without name",
+			content: "struct Test {\n  1: required i32 \n}",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 1, Character: 2}, End: protocol.Position{Line: 1, Character: 3}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "expected a field name after type 'i32'",
+				},
+			},
+		},
+		{
+			name:    "separator after definition",
+			content: "struct Test {\n  1: i32 a\n};\n",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 2, Character: 1}, End: protocol.Position{Line: 2, Character: 2}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "unexpected ';', separators are not allowed after a definition",
+				},
+			},
+		},
+		{
+			name:    "unclosed block",
+			content: "struct Test {\n  1: i32 a\n\nstruct Test2 {\n  1: i32 b\n}\n",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 3, Character: 0}, End: protocol.Position{Line: 3, Character: 6}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "'{' of struct Test is not closed, expected '}' before 'struct'",
+					RelatedInformation: []protocol.DiagnosticRelatedInformation{
+						{
+							Location: protocol.Location{
+								URI:   "file:///tmp/user.thrift",
+								Range: protocol.Range{Start: protocol.Position{Line: 0, Character: 12}, End: protocol.Position{Line: 0, Character: 13}},
+							},
+							Message: "struct Test starts here",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "unclosed block at end of file",
+			content: "enum Status {\n  OK,\n  Failed\n",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 3, Character: 0}, End: protocol.Position{Line: 3, Character: 0}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "'{' of enum Status is not closed, expected '}' before end of file",
+					RelatedInformation: []protocol.DiagnosticRelatedInformation{
+						{
+							Location: protocol.Location{
+								URI:   "file:///tmp/user.thrift",
+								Range: protocol.Range{Start: protocol.Position{Line: 0, Character: 12}, End: protocol.Position{Line: 0, Character: 13}},
+							},
+							Message: "enum Status starts here",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "unclosed string literal",
+			content: "struct Test {\n  1: string a = \"xx\n}",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 1, Character: 16}, End: protocol.Position{Line: 1, Character: 19}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "string literal is not closed, expected a closing '\"'",
+				},
+			},
+		},
+		{
+			name:    "invalid include",
+			content: "include base.thrift\n",
+			want: []protocol.Diagnostic{
+				{
+					Range:    protocol.Range{Start: protocol.Position{Line: 0, Character: 0}, End: protocol.Position{Line: 0, Character: 7}},
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  "unexpected 'include', expected a quoted file path like 'include \"base.thrift\"'",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := uri.URI("file:///tmp/user.thrift")
+			ss := buildSnapshotForTest([]*cache.FileChange{
+				{
+					URI:     file,
+					Version: 0,
+					Content: []byte(tt.content),
+					From:    cache.FileChangeTypeDidOpen,
+				},
+			})
+			got, err := (&Parse{}).Diagnostic(context.TODO(), ss, []uri.URI{file})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got[file])
+		})
+	}
+}
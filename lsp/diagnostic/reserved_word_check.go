@@ -0,0 +1,171 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// ReservedWordCheck reports names which are reserved words of target languages. Target languages
+// are namespace scopes of file and languages configured in options
+type ReservedWordCheck struct {
+}
+
+func (c *ReservedWordCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	res := make(DiagnosticResult)
+	for _, file := range changeFiles {
+		items, err := c.diagnostic(ctx, ss, file)
+		if err != nil {
+			return nil, err
+		}
+		res[file] = items
+	}
+
+	return res, nil
+}
+
+func (c *ReservedWordCheck) Name() string {
+	return "ReservedWordCheck"
+}
+
+func (c *ReservedWordCheck) diagnostic(ctx context.Context, ss *cache.Snapshot, file uri.URI) ([]protocol.Diagnostic, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	opts := OptionsFromContext(ctx)
+	sev, ok := severity(opts.ReservedWord, protocol.DiagnosticSeverityWarning)
+	if !ok {
+		return nil, nil
+	}
+
+	languages := targetLanguages(pf.AST(), opts.TargetLanguages)
+	if len(languages) == 0 {
+		return nil, nil
+	}
+
+	var ret []protocol.Diagnostic
+	check := func(id *parser.Identifier) {
+		if id == nil || id.BadNode || id.Name == nil {
+			return
+		}
+		var conflicts []string
+		for _, lang := range languages {
+			if _, ok := reservedWords[lang][id.Name.Text]; ok {
+				conflicts = append(conflicts, lang)
+			}
+		}
+		if len(conflicts) == 0 {
+			return
+		}
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(id),
+			Severity: sev,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("%s is a reserved word in %s", id.Name.Text, strings.Join(conflicts, ", ")),
+		})
+	}
+	checkFields := func(fields []*parser.Field) {
+		for _, field := range fields {
+			if !field.BadNode {
+				check(field.Identifier)
+			}
+		}
+	}
+
+	for _, node := range pf.AST().Nodes {
+		if node.IsBadNode() {
+			continue
+		}
+		switch n := node.(type) {
+		case *parser.Struct:
+			check(n.Identifier)
+			checkFields(n.Fields)
+		case *parser.Union:
+			check(n.Name)
+			checkFields(n.Fields)
+		case *parser.Exception:
+			check(n.Name)
+			checkFields(n.Fields)
+		case *parser.Enum:
+			check(n.Name)
+			for _, v := range n.Values {
+				if !v.BadNode {
+					check(v.Name)
+				}
+			}
+		case *parser.Service:
+			check(n.Name)
+			for _, fn := range n.Functions {
+				if fn.BadNode {
+					continue
+				}
+				check(fn.Name)
+				checkFields(fn.Arguments)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+var reservedWords = map[string]map[string]struct{}{
+	LanguageGo: toSet(
+		"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+		"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range",
+		"return", "select", "struct", "switch", "type", "var",
+	),
+	LanguageJava: toSet(
+		"abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const",
+		"continue", "default", "do", "double", "else", "enum", "extends", "final", "finally", "float",
+		"for", "goto", "if", "implements", "import", "instanceof", "int", "interface", "long", "native",
+		"new", "package", "private", "protected", "public", "return", "short", "static", "strictfp",
+		"super", "switch", "synchronized", "this", "throw", "throws", "transient", "try", "void",
+		"volatile", "while", "true", "false", "null",
+	),
+	LanguagePython: toSet(
+		"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue",
+		"def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import",
+		"in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while",
+		"with", "yield",
+	),
+	LanguageCPP: toSet(
+		"alignas", "alignof", "and", "and_eq", "asm", "auto", "bitand", "bitor", "bool", "break",
+		"case", "catch", "char", "char16_t", "char32_t", "class", "compl", "const", "constexpr",
+		"const_cast", "continue", "decltype", "default", "delete", "do", "double", "dynamic_cast",
+		"else", "enum", "explicit", "export", "extern", "false", "float", "for", "friend", "goto",
+		"if", "inline", "int", "long", "mutable", "namespace", "new", "noexcept", "not", "not_eq",
+		"nullptr", "operator", "or", "or_eq", "private", "protected", "public", "register",
+		"reinterpret_cast", "return", "short", "signed", "sizeof", "static", "static_assert",
+		"static_cast", "struct", "switch", "template", "this", "thread_local", "throw", "true", "try",
+		"typedef", "typeid", "typename", "union", "unsigned", "using", "virtual", "void", "volatile",
+		"wchar_t", "while", "xor", "xor_eq",
+	),
+	LanguageJavaScript: toSet(
+		"await", "break", "case", "catch", "class", "const", "continue", "debugger", "default",
+		"delete", "do", "else", "enum", "export", "extends", "false", "finally", "for", "function",
+		"if", "implements", "import", "in", "instanceof", "interface", "let", "new", "null",
+		"package", "private", "protected", "public", "return", "static", "super", "switch", "this",
+		"throw", "true", "try", "typeof", "var", "void", "while", "with", "yield",
+	),
+}
+
+func toSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,113 @@
+package diagnostic
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_ReservedWordCheck_Diagnostic(t *testing.T) {
+	file := `namespace go user
+namespace py user
+
+struct User {
+  1: string type
+  2: string from
+  3: string class
+}
+
+enum Status {
+  None
+  OK
+}
+
+service UserService {
+  void func(1: i64 default)
+}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(file),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type diag struct {
+		line      uint32
+		character uint32
+		message   string
+	}
+	tests := []struct {
+		name string
+		opts *Options
+		want []diag
+	}{
+		{
+			name: "namespace languages",
+			opts: &Options{},
+			want: []diag{
+				{line: 4, character: 12, message: "type is a reserved word in go"},
+				{line: 5, character: 12, message: "from is a reserved word in python"},
+				{line: 6, character: 12, message: "class is a reserved word in python"},
+				{line: 10, character: 2, message: "None is a reserved word in python"},
+				{line: 15, character: 7, message: "func is a reserved word in go"},
+				{line: 15, character: 19, message: "default is a reserved word in go"},
+			},
+		},
+		{
+			name: "configured languages",
+			opts: &Options{ReservedWord: SeverityError, TargetLanguages: []string{"java"}},
+			want: []diag{
+				{line: 4, character: 12, message: "type is a reserved word in go"},
+				{line: 5, character: 12, message: "from is a reserved word in python"},
+				{line: 6, character: 12, message: "class is a reserved word in java, python"},
+				{line: 10, character: 2, message: "None is a reserved word in python"},
+				{line: 15, character: 7, message: "func is a reserved word in go"},
+				{line: 15, character: 19, message: "default is a reserved word in go, java"},
+			},
+		},
+		{
+			name: "javascript",
+			opts: &Options{TargetLanguages: []string{"js"}},
+			want: []diag{
+				{line: 4, character: 12, message: "type is a reserved word in go"},
+				{line: 5, character: 12, message: "from is a reserved word in python"},
+				{line: 6, character: 12, message: "class is a reserved word in python, js"},
+				{line: 10, character: 2, message: "None is a reserved word in python"},
+				{line: 15, character: 7, message: "func is a reserved word in go"},
+				{line: 15, character: 19, message: "default is a reserved word in go, js"},
+			},
+		},
+		{
+			name: "ignored",
+			opts: &Options{ReservedWord: SeverityIgnore},
+			want: []diag{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ReservedWordCheck{}
+			res, err := c.Diagnostic(WithOptions(context.TODO(), tt.opts), ss, []uri.URI{"file:///tmp/user.thrift"})
+			assert.NoError(t, err)
+
+			items := res["file:///tmp/user.thrift"]
+			sort.SliceStable(items, func(i, j int) bool {
+				if items[i].Range.Start.Line == items[j].Range.Start.Line {
+					return items[i].Range.Start.Character < items[j].Range.Start.Character
+				}
+				return items[i].Range.Start.Line < items[j].Range.Start.Line
+			})
+			got := make([]diag, 0, len(items))
+			for _, item := range items {
+				got = append(got, diag{line: item.Range.Start.Line, character: item.Range.Start.Character, message: item.Message})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -52,9 +52,119 @@ func (s *SemanticAnalysis) diagnostic(ctx context.Context, ss *cache.Snapshot, c
 	items := s.checkDefinitionExist(ctx, ss, changeFile, pf)
 	res = append(res, items...)
 
+	items = s.checkUnions(pf.AST())
+	res = append(res, items...)
+
+	items = s.checkExceptions(ctx, ss, changeFile, pf.AST())
+	res = append(res, items...)
+
 	return res, nil
 }
 
+// checkUnions checks union rules: no required fields, at most one default value and at least one field
+func (s *SemanticAnalysis) checkUnions(ast *parser.Document) []protocol.Diagnostic {
+	var ret []protocol.Diagnostic
+	for _, union := range ast.Unions {
+		if union.BadNode || union.Name == nil || union.Name.Name == nil {
+			continue
+		}
+
+		if len(union.Fields) == 0 {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    lsputils.ASTNodeToRange(union.Name),
+				Severity: protocol.DiagnosticSeverityWarning,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("union %s has no fields", union.Name.Name.Text),
+			})
+		}
+
+		var defaultField *parser.Field
+		for _, field := range union.Fields {
+			if field.BadNode || field.Identifier == nil || field.Identifier.Name == nil {
+				continue
+			}
+			if field.RequiredKeyword != nil && field.RequiredKeyword.Literal != nil &&
+				field.RequiredKeyword.Literal.Text == "required" {
+				ret = append(ret, protocol.Diagnostic{
+					Range:    lsputils.ASTNodeToRange(field.RequiredKeyword),
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message:  fmt.Sprintf("union field %s can't be required", field.Identifier.Name.Text),
+				})
+			}
+			if field.ConstValue == nil {
+				continue
+			}
+			if defaultField != nil {
+				ret = append(ret, protocol.Diagnostic{
+					Range:    lsputils.ASTNodeToRange(field.ConstValue),
+					Severity: protocol.DiagnosticSeverityError,
+					Source:   "thrift-ls",
+					Message: fmt.Sprintf("union %s has more than one default value, %s already has default value",
+						union.Name.Name.Text, defaultField.Identifier.Name.Text),
+				})
+				continue
+			}
+			defaultField = field
+		}
+	}
+
+	return ret
+}
+
+// checkExceptions checks exceptions are not used as const types, and field `message` of exception
+// is string. Structs in throws are reported by ServiceCheck, and exceptions used as map keys or set
+// elements are reported by ContainerKeyCheck
+func (s *SemanticAnalysis) checkExceptions(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document) []protocol.Diagnostic {
+	var ret []protocol.Diagnostic
+	isException := func(ft *parser.FieldType) bool {
+		resolved, err := codejump.ResolveFieldType(ctx, ss, file, ast, ft)
+		if err != nil || resolved == nil {
+			return false
+		}
+		_, ok := resolved.Definition.(*parser.Exception)
+		return ok
+	}
+
+	for _, cst := range ast.Consts {
+		if cst.BadNode || cst.ConstType == nil || cst.ConstType.BadNode || cst.ConstType.TypeName == nil {
+			continue
+		}
+		if isException(cst.ConstType) {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    lsputils.ASTNodeToRange(cst.ConstType),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("exception %s can't be used as const type", cst.ConstType.TypeName.Name),
+			})
+		}
+	}
+
+	for _, excep := range ast.Exceptions {
+		if excep.BadNode || excep.Name == nil || excep.Name.Name == nil {
+			continue
+		}
+		for _, field := range excep.Fields {
+			if field.BadNode || field.ChildrenBadNode() || field.Identifier.Name.Text != "message" {
+				continue
+			}
+			resolved, err := codejump.ResolveFieldType(ctx, ss, file, ast, field.FieldType)
+			if err != nil || resolved == nil || resolved.FieldType.TypeName.Name == "string" {
+				continue
+			}
+			ret = append(ret, protocol.Diagnostic{
+				Range:    lsputils.ASTNodeToRange(field.FieldType),
+				Severity: protocol.DiagnosticSeverityWarning,
+				Source:   "thrift-ls",
+				Message: fmt.Sprintf("field message of exception %s should be string, "+
+					"some generators use it as error message", excep.Name.Name.Text),
+			})
+		}
+	}
+
+	return ret
+}
+
 func (s *SemanticAnalysis) checkDefineConflict(ctx context.Context, pf *cache.ParsedFile) []protocol.Diagnostic {
 	var ret []protocol.Diagnostic
 
@@ -250,10 +360,8 @@ func (s *SemanticAnalysis) checkDefinitionExist(ctx context.Context, ss *cache.S
 				items := s.checkConstValueExist(ctx, ss, file, pf, field.ConstValue)
 				ret = append(ret, items...)
 
-				dig := s.checkConstValueMatchType(ctx, field)
-				if dig != nil {
-					ret = append(ret, *dig)
-				}
+				items = s.checkConstValueType(ctx, ss, file, pf.AST(), field.FieldType, field.ConstValue)
+				ret = append(ret, items...)
 			}
 		}
 	}
@@ -271,7 +379,24 @@ func (s *SemanticAnalysis) checkDefinitionExist(ctx context.Context, ss *cache.S
 	}
 
 	for _, cst := range pf.AST().Consts {
-		items := s.checkConstValueExist(ctx, ss, file, pf, cst.Value)
+		if cst.IsBadNode() || cst.ChildrenBadNode() {
+			continue
+		}
+		items := s.checkTypeExist(ctx, ss, file, pf, cst.ConstType)
+		ret = append(ret, items...)
+
+		items = s.checkConstValueExist(ctx, ss, file, pf, cst.Value)
+		ret = append(ret, items...)
+
+		items = s.checkConstValueType(ctx, ss, file, pf.AST(), cst.ConstType, cst.Value)
+		ret = append(ret, items...)
+	}
+
+	for _, td := range pf.AST().Typedefs {
+		if td.IsBadNode() || td.ChildrenBadNode() {
+			continue
+		}
+		items := s.checkTypeExist(ctx, ss, file, pf, td.T)
 		ret = append(ret, items...)
 	}
 
@@ -294,7 +419,31 @@ func (s *SemanticAnalysis) checkDefinitionExist(ctx context.Context, ss *cache.S
 
 func (s *SemanticAnalysis) checkConstValueExist(ctx context.Context, ss *cache.Snapshot,
 	file uri.URI, pf *cache.ParsedFile, cst *parser.ConstValue) (res []protocol.Diagnostic) {
-	if cst.TypeName != "identifier" {
+	if cst == nil || cst.BadNode {
+		return
+	}
+
+	switch cst.TypeName {
+	case "list":
+		values, _ := cst.Value.([]*parser.ConstValue)
+		for _, item := range values {
+			res = append(res, s.checkConstValueExist(ctx, ss, file, pf, item)...)
+		}
+		return
+	case "map":
+		values, _ := cst.Value.([]*parser.ConstValue)
+		for _, pair := range values {
+			if pair == nil || pair.BadNode || pair.TypeName != "pair" {
+				continue
+			}
+			key, _ := pair.Key.(*parser.ConstValue)
+			value, _ := pair.Value.(*parser.ConstValue)
+			res = append(res, s.checkConstValueExist(ctx, ss, file, pf, key)...)
+			res = append(res, s.checkConstValueExist(ctx, ss, file, pf, value)...)
+		}
+		return
+	case "identifier":
+	default:
 		return
 	}
 
@@ -304,75 +453,36 @@ func (s *SemanticAnalysis) checkConstValueExist(ctx context.Context, ss *cache.S
 
 	_, id, err := codejump.ConstValueTypeDefinitionIdentifier(ctx, ss, file, pf.AST(), cst)
 	if err != nil || id == nil {
-		res = append(res, protocol.Diagnostic{
+		name, _ := cst.Value.(string)
+		res = append(res, withSuggestions(protocol.Diagnostic{
 			Range:    lsputils.ASTNodeToRange(cst),
 			Severity: protocol.DiagnosticSeverityError,
 			Source:   "thrift-ls",
-			Message:  fmt.Sprintf("default value doesn't exist"),
-		})
+			Message:  "default value doesn't exist",
+		}, cst, suggest(name, valueCandidates(ctx, ss, file, pf.AST()))))
 	}
 
 	return
 }
 
-func (s *SemanticAnalysis) checkConstValueMatchType(ctx context.Context, field *parser.Field) (res *protocol.Diagnostic) {
-	if field.BadNode || field.ChildrenBadNode() {
-		return nil
-	}
-
-	expectTypeName := field.FieldType.TypeName
-
-	if field.ConstValue != nil {
-		valueType := field.ConstValue.TypeName
-		// TypeName can be: list, map, pair, string, identifier, i64, double
-		switch valueType {
-		case "list", "map", "string", "double":
-			if expectTypeName.Name != valueType {
-				return &protocol.Diagnostic{
-					Range:    lsputils.ASTNodeToRange(field.ConstValue),
-					Severity: protocol.DiagnosticSeverityError,
-					Source:   "thrift-ls",
-					Message:  fmt.Sprintf("expect %s but got %s", expectTypeName.Name, valueType),
-				}
-			}
-		case "identifier":
-			if valueType == "identifier" &&
-				(field.ConstValue.Value == "true" || field.ConstValue.Value == "false") {
-				valueType = "bool"
-			}
-			if expectTypeName.Name == "bool" {
-				if field.ConstValue.Value != "true" && field.ConstValue.Value != "false" {
-					return &protocol.Diagnostic{
-						Range:    lsputils.ASTNodeToRange(field.ConstValue),
-						Severity: protocol.DiagnosticSeverityError,
-						Source:   "thrift-ls",
-						Message:  fmt.Sprintf("expect %s but got %s", expectTypeName.Name, valueType),
-					}
-				}
-			} else if codejump.IsBasicType(valueType) {
-				return &protocol.Diagnostic{
-					Range:    lsputils.ASTNodeToRange(field.ConstValue),
-					Severity: protocol.DiagnosticSeverityError,
-					Source:   "thrift-ls",
-					Message:  fmt.Sprintf("expect %s but got %s", expectTypeName.Name, valueType),
-				}
-			}
-		case "i64":
-			if expectTypeName.Name != "i8" &&
-				expectTypeName.Name != "i16" &&
-				expectTypeName.Name != "i32" &&
-				expectTypeName.Name != "i64" {
-				return &protocol.Diagnostic{
-					Range:    lsputils.ASTNodeToRange(field.ConstValue),
-					Severity: protocol.DiagnosticSeverityError,
-					Source:   "thrift-ls",
-					Message:  fmt.Sprintf("expect %s but got %s", expectTypeName.Name, valueType),
-				}
-			}
-		}
-	}
-
-	return nil
+// javaStyleTypes maps type names of other languages to thrift base types
+var javaStyleTypes = map[string]string{
+	"String":  "string",
+	"Boolean": "bool",
+	"boolean": "bool",
+	"Byte":    "byte",
+	"Short":   "i16",
+	"short":   "i16",
+	"Integer": "i32",
+	"int":     "i32",
+	"Long":    "i64",
+	"long":    "i64",
+	"Double":  "double",
+	"float":   "double",
+	"Float":   "double",
+	"List":    "list",
+	"Set":     "set",
+	"Map":     "map",
 }
 
 func (s *SemanticAnalysis) checkTypeExist(ctx context.Context, ss *cache.Snapshot,
@@ -384,12 +494,18 @@ func (s *SemanticAnalysis) checkTypeExist(ctx context.Context, ss *cache.Snapsho
 	} else {
 		_, id, _, err := codejump.TypeNameDefinitionIdentifier(ctx, ss, file, pf.AST(), ft.TypeName)
 		if err != nil || id == nil {
-			res = append(res, protocol.Diagnostic{
+			var suggestions []string
+			if t, ok := javaStyleTypes[ft.TypeName.Name]; ok {
+				suggestions = []string{t}
+			} else {
+				suggestions = suggest(ft.TypeName.Name, typeCandidates(ctx, ss, file, pf.AST()))
+			}
+			res = append(res, withSuggestions(protocol.Diagnostic{
 				Range:    lsputils.ASTNodeToRange(ft),
 				Severity: protocol.DiagnosticSeverityError,
 				Source:   "thrift-ls",
-				Message:  fmt.Sprintf("field type doesn't exist"),
-			})
+				Message:  "field type doesn't exist",
+			}, ft.TypeName, suggestions))
 		}
 	}
 
@@ -411,5 +527,3 @@ func (s *SemanticAnalysis) checkContainerTypeExist(ctx context.Context,
 
 	return res
 }
-
-// TODO(jpf): 类型和默认值的类型要一致
@@ -113,6 +113,36 @@ struct TestUUID {
 						Source:   "thrift-ls",
 						Message:  "struct name conflict with other struct",
 					},
+					{
+						Range: protocol.Range{
+							Start: protocol.Position{
+								Line:      9,
+								Character: 4,
+							},
+							End: protocol.Position{
+								Line:      9,
+								Character: 13,
+							},
+						},
+						Severity: protocol.DiagnosticSeverityError,
+						Source:   "thrift-ls",
+						Message:  "union field name can't be required",
+					},
+					{
+						Range: protocol.Range{
+							Start: protocol.Position{
+								Line:      10,
+								Character: 4,
+							},
+							End: protocol.Position{
+								Line:      10,
+								Character: 13,
+							},
+						},
+						Severity: protocol.DiagnosticSeverityError,
+						Source:   "thrift-ls",
+						Message:  "union field user1 can't be required",
+					},
 					{
 						Range: protocol.Range{
 							Start: protocol.Position{
@@ -128,6 +158,21 @@ struct TestUUID {
 						Source:   "thrift-ls",
 						Message:  "field type doesn't exist",
 					},
+					{
+						Range: protocol.Range{
+							Start: protocol.Position{
+								Line:      11,
+								Character: 4,
+							},
+							End: protocol.Position{
+								Line:      11,
+								Character: 13,
+							},
+						},
+						Severity: protocol.DiagnosticSeverityError,
+						Source:   "thrift-ls",
+						Message:  "union field user2 can't be required",
+					},
 					{
 						Range: protocol.Range{
 							Start: protocol.Position{
@@ -274,3 +319,165 @@ struct TestUUID {
 		})
 	}
 }
+
+func Test_SemanticAnalysis_ConstValueType(t *testing.T) {
+	base := `typedef i16 Short
+typedef list<Short> Shorts
+
+enum Color {
+  RED = 1,
+  GREEN
+}
+
+struct Point {
+  1: i32 x
+  2: i32 y
+}
+`
+	user := `include "base.thrift"
+
+enum Status {
+  OK
+}
+// line 6
+const base.Shorts Values = [1, 70000, "a"]
+const map<string, base.Color> Colors = {"a": base.Color.RED, "b": Status.OK, "c": 5}
+const base.Point Origin = {"x": 1, "z": 2, "y": "1"}
+const i8 Small = 128
+const base.Color Green = 2
+
+struct User {
+  1: i32 x = "a"
+  2: list<base.Point> points = [{"x": 1}, [1]]
+  3: base.Color color = base.Color.BLUE
+}
+typedef list<base.Pointt> Points
+const Status DefaultStatus = Status.OKK
+const Color DefaultColor = base.Color.RED
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/base.thrift",
+			Version: 0,
+			Content: []byte(base),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(user),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type diag struct {
+		line      uint32
+		character uint32
+		message   string
+	}
+	want := []diag{
+		{line: 6, character: 31, message: "value 70000 overflows Short, range is [-32768, 32767]"},
+		{line: 6, character: 38, message: "expect Short but got string"},
+		{line: 7, character: 66, message: "expect a value of enum base.Color but got a value of enum Status"},
+		{line: 7, character: 82, message: "5 is not a value of enum Color"},
+		{line: 8, character: 35, message: "unknown field 'z' of struct Point"},
+		{line: 8, character: 48, message: "expect i32 but got string"},
+		{line: 9, character: 17, message: "value 128 overflows i8, range is [-128, 127]"},
+		{line: 13, character: 13, message: "expect i32 but got string"},
+		{line: 14, character: 42, message: "expect Point but got list"},
+		{line: 15, character: 24, message: "default value doesn't exist"},
+		{line: 17, character: 13, message: "field type doesn't exist, did you mean 'base.Point'?"},
+		{line: 18, character: 29, message: "default value doesn't exist, did you mean 'Status.OK'?"},
+		{line: 19, character: 6, message: "field type doesn't exist, did you mean 'base.Color'?"},
+	}
+
+	c := &SemanticAnalysis{}
+	res, err := c.Diagnostic(context.TODO(), ss, []uri.URI{"file:///tmp/user.thrift"})
+	assert.NoError(t, err)
+
+	items := res["file:///tmp/user.thrift"]
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Range.Start.Line == items[j].Range.Start.Line {
+			return items[i].Range.Start.Character < items[j].Range.Start.Character
+		}
+		return items[i].Range.Start.Line < items[j].Range.Start.Line
+	})
+	got := make([]diag, 0, len(items))
+	for _, item := range items {
+		got = append(got, diag{line: item.Range.Start.Line, character: item.Range.Start.Character, message: item.Message})
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_SemanticAnalysis_UnionAndException(t *testing.T) {
+	base := `exception Error {
+  1: i32 message
+}
+typedef Error Err
+`
+	user := `include "base.thrift"
+
+union Empty {}
+
+union Value {
+  1: required string s
+  2: i32 i = 1
+  3: i64 l = 2
+}
+
+exception MyError {
+  1: string message
+}
+
+const base.Err DefaultError = {}
+struct Cache {
+  1: map<base.Error, string> errors
+}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/base.thrift",
+			Version: 0,
+			Content: []byte(base),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(user),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type diag struct {
+		line      uint32
+		character uint32
+		message   string
+	}
+	want := []diag{
+		{line: 2, character: 6, message: "union Empty has no fields"},
+		{line: 5, character: 5, message: "union field s can't be required"},
+		{line: 7, character: 13, message: "union Value has more than one default value, i already has default value"},
+		{line: 14, character: 6, message: "exception base.Err can't be used as const type"},
+	}
+
+	c := &SemanticAnalysis{}
+	res, err := c.Diagnostic(context.TODO(), ss, []uri.URI{"file:///tmp/user.thrift"})
+	assert.NoError(t, err)
+
+	items := res["file:///tmp/user.thrift"]
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Range.Start.Line < items[j].Range.Start.Line
+	})
+	got := make([]diag, 0, len(items))
+	for _, item := range items {
+		got = append(got, diag{line: item.Range.Start.Line, character: item.Range.Start.Character, message: item.Message})
+	}
+	assert.Equal(t, want, got)
+
+	res, err = c.Diagnostic(context.TODO(), ss, []uri.URI{"file:///tmp/base.thrift"})
+	assert.NoError(t, err)
+	assert.Len(t, res["file:///tmp/base.thrift"], 1)
+	assert.Equal(t, "field message of exception Error should be string, some generators use it as error message",
+		res["file:///tmp/base.thrift"][0].Message)
+}
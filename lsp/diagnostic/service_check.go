@@ -0,0 +1,206 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// ServiceCheck checks oneway functions, throws types and service inheritance.
+// These errors are reported by Apache compiler.
+type ServiceCheck struct {
+}
+
+func (c *ServiceCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	res := make(DiagnosticResult)
+	for _, file := range changeFiles {
+		items, err := c.diagnostic(ctx, ss, file)
+		if err != nil {
+			return nil, err
+		}
+		res[file] = items
+	}
+
+	return res, nil
+}
+
+func (c *ServiceCheck) Name() string {
+	return "ServiceCheck"
+}
+
+func (c *ServiceCheck) diagnostic(ctx context.Context, ss *cache.Snapshot, file uri.URI) ([]protocol.Diagnostic, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	var ret []protocol.Diagnostic
+	for _, svc := range pf.AST().Services {
+		if svc.BadNode || svc.Name == nil || svc.Name.Name == nil {
+			continue
+		}
+
+		for _, fn := range svc.Functions {
+			if fn.BadNode || fn.ChildrenBadNode() {
+				continue
+			}
+			ret = append(ret, c.checkOneway(fn)...)
+			ret = append(ret, c.checkThrows(ctx, ss, file, pf.AST(), fn)...)
+		}
+
+		ret = append(ret, c.checkExtends(ctx, ss, file, pf.AST(), svc)...)
+	}
+
+	return ret, nil
+}
+
+func (c *ServiceCheck) checkOneway(fn *parser.Function) (ret []protocol.Diagnostic) {
+	if fn.Oneway == nil {
+		return nil
+	}
+
+	if fn.Void == nil && fn.FunctionType != nil {
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(fn.FunctionType),
+			Severity: protocol.DiagnosticSeverityError,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("oneway function %s must return void", fn.Name.Name.Text),
+		})
+	}
+
+	if fn.Throws != nil && fn.Throws.ThrowsKeyword != nil {
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(fn.Throws.ThrowsKeyword),
+			Severity: protocol.DiagnosticSeverityError,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("oneway function %s can't throw exceptions", fn.Name.Name.Text),
+		})
+	}
+
+	return ret
+}
+
+func (c *ServiceCheck) checkThrows(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, fn *parser.Function) (ret []protocol.Diagnostic) {
+	if fn.Throws == nil {
+		return nil
+	}
+
+	for _, field := range fn.Throws.Fields {
+		if field.BadNode || field.FieldType == nil || field.FieldType.TypeName == nil {
+			continue
+		}
+		resolved, err := codejump.ResolveFieldType(ctx, ss, file, ast, field.FieldType)
+		if err != nil || resolved == nil {
+			// undefined type is reported by SemanticAnalysis
+			continue
+		}
+		if _, ok := resolved.Definition.(*parser.Exception); ok {
+			continue
+		}
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(field.FieldType),
+			Severity: protocol.DiagnosticSeverityError,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("throws type %s is not an exception", field.FieldType.TypeName.Name),
+		})
+	}
+
+	return ret
+}
+
+func (c *ServiceCheck) checkExtends(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document, svc *parser.Service) (ret []protocol.Diagnostic) {
+	if svc.Extends == nil || svc.Extends.BadNode || svc.Extends.Name == nil {
+		return nil
+	}
+	extends := svc.Extends.Name.Text
+	rng := lsputils.ASTNodeToRange(svc.Extends.Name)
+
+	_, _, parent, node, err := codejump.ResolveService(ctx, ss, file, ast, extends)
+	if err != nil {
+		return nil
+	}
+	if parent == nil {
+		message := fmt.Sprintf("extends service %s doesn't exist", extends)
+		if node != nil {
+			message = fmt.Sprintf("%s is a %s, only service can be extended", extends, strings.ToLower(node.Type()))
+		}
+		return []protocol.Diagnostic{
+			{
+				Range:    rng,
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  message,
+			},
+		}
+	}
+
+	// walk parents, stop at the first cycle
+	start := serviceKey(file, svc)
+	visited := map[string]struct{}{start: {}}
+	path := []string{svc.Name.Name.Text}
+	inherited := make(map[string]string)
+	curFile, curAst, cur := file, ast, svc
+	for cur.Extends != nil && !cur.Extends.BadNode && cur.Extends.Name != nil {
+		parentFile, parentAst, parent, _, err := codejump.ResolveService(ctx, ss, curFile, curAst, cur.Extends.Name.Text)
+		if err != nil || parent == nil || parent.Name == nil || parent.Name.Name == nil {
+			break
+		}
+		key := serviceKey(parentFile, parent)
+		path = append(path, parent.Name.Name.Text)
+		if key == start {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    rng,
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("service extends cycle: %s", strings.Join(path, " -> ")),
+			})
+			break
+		}
+		if _, ok := visited[key]; ok {
+			break
+		}
+		visited[key] = struct{}{}
+
+		for _, fn := range parent.Functions {
+			if fn.BadNode || fn.Name == nil || fn.Name.Name == nil {
+				continue
+			}
+			if _, ok := inherited[fn.Name.Name.Text]; !ok {
+				inherited[fn.Name.Name.Text] = parent.Name.Name.Text
+			}
+		}
+		curFile, curAst, cur = parentFile, parentAst, parent
+	}
+
+	for _, fn := range svc.Functions {
+		if fn.BadNode || fn.Name == nil || fn.Name.Name == nil {
+			continue
+		}
+		if parentName, ok := inherited[fn.Name.Name.Text]; ok {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    lsputils.ASTNodeToRange(fn.Name.Name),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("function %s is already defined in parent service %s", fn.Name.Name.Text, parentName),
+			})
+		}
+	}
+
+	return ret
+}
+
+func serviceKey(file uri.URI, svc *parser.Service) string {
+	return string(file) + "#" + svc.Name.Name.Text
+}
@@ -0,0 +1,87 @@
+package diagnostic
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_ServiceCheck_Diagnostic(t *testing.T) {
+	base := `include "user.thrift"
+
+exception Error {
+  1: string message
+}
+
+struct Req {}
+
+service Base extends user.Loop {
+  void Ping()
+}
+`
+	user := `include "base.thrift"
+
+service Loop extends base.Base {
+}
+
+service UserService extends base.Base {
+  oneway i32 Notify() throws (1: base.Error err)
+  void Ping()
+  void Get() throws (1: base.Req req, 2: base.Error err, 3: string msg)
+}
+
+service Wrong extends base.Req {}
+service Missing extends Nothing {}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/base.thrift",
+			Version: 0,
+			Content: []byte(base),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(user),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type diag struct {
+		line      uint32
+		character uint32
+		message   string
+	}
+	want := []diag{
+		{line: 2, character: 21, message: "service extends cycle: Loop -> Base -> Loop"},
+		{line: 6, character: 9, message: "oneway function Notify must return void"},
+		{line: 6, character: 22, message: "oneway function Notify can't throw exceptions"},
+		{line: 7, character: 7, message: "function Ping is already defined in parent service Base"},
+		{line: 8, character: 24, message: "throws type base.Req is not an exception"},
+		{line: 8, character: 60, message: "throws type string is not an exception"},
+		{line: 11, character: 22, message: "base.Req is a struct, only service can be extended"},
+		{line: 12, character: 24, message: "extends service Nothing doesn't exist"},
+	}
+
+	c := &ServiceCheck{}
+	res, err := c.Diagnostic(context.TODO(), ss, []uri.URI{"file:///tmp/user.thrift"})
+	assert.NoError(t, err)
+
+	items := res["file:///tmp/user.thrift"]
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Range.Start.Line == items[j].Range.Start.Line {
+			return items[i].Range.Start.Character < items[j].Range.Start.Character
+		}
+		return items[i].Range.Start.Line < items[j].Range.Start.Line
+	})
+	got := make([]diag, 0, len(items))
+	for _, item := range items {
+		got = append(got, diag{line: item.Range.Start.Line, character: item.Range.Start.Character, message: item.Message})
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,209 @@
+package diagnostic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"github.com/joyme123/thrift-ls/utils"
+	"go.lsp.dev/uri"
+)
+
+// maxSuggestions limits suggestions of one undefined name
+const maxSuggestions = 3
+
+// SuggestionData is attached to diagnostic as Data. Client sends it back in code action request,
+// each suggestion becomes a quick fix replacing Range
+type SuggestionData struct {
+	Range       protocol.Range `json:"range"`
+	Suggestions []string       `json:"suggestions"`
+}
+
+// withSuggestions appends best suggestion to message of d and attaches all suggestions as data
+func withSuggestions(d protocol.Diagnostic, node parser.Node, suggestions []string) protocol.Diagnostic {
+	if len(suggestions) == 0 {
+		return d
+	}
+	d.Message = fmt.Sprintf("%s, did you mean '%s'?", d.Message, suggestions[0])
+	d.Data = &SuggestionData{
+		Range:       lsputils.ASTNodeToRange(node),
+		Suggestions: suggestions,
+	}
+	return d
+}
+
+// suggestionData converts diagnostic data to SuggestionData. Data is a json object
+// if diagnostic is sent back by client
+func suggestionData(data interface{}) *SuggestionData {
+	switch v := data.(type) {
+	case nil:
+		return nil
+	case *SuggestionData:
+		return v
+	case SuggestionData:
+		return &v
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	res := &SuggestionData{}
+	if err := json.Unmarshal(raw, res); err != nil || len(res.Suggestions) == 0 {
+		return nil
+	}
+	return res
+}
+
+// QuickFixes returns code actions replacing undefined names by suggestions attached to diagnostics
+func QuickFixes(file uri.URI, diagnostics []protocol.Diagnostic) []protocol.CodeAction {
+	res := make([]protocol.CodeAction, 0)
+	for _, d := range diagnostics {
+		if d.Source != "thrift-ls" {
+			continue
+		}
+		data := suggestionData(d.Data)
+		if data == nil {
+			continue
+		}
+		for i, suggestion := range data.Suggestions {
+			res = append(res, protocol.CodeAction{
+				Title:       fmt.Sprintf("Change to '%s'", suggestion),
+				Kind:        protocol.QuickFix,
+				Diagnostics: []protocol.Diagnostic{d},
+				IsPreferred: i == 0,
+				Edit: &protocol.WorkspaceEdit{
+					Changes: map[uri.URI][]protocol.TextEdit{
+						file: {{Range: data.Range, NewText: suggestion}},
+					},
+				},
+			})
+		}
+	}
+
+	return res
+}
+
+// suggest returns candidates similar to name, most similar first. Candidates are also compared
+// without include prefix, so `base.User` is suggested for `User`
+func suggest(name string, candidates []string) []string {
+	prefix, bareName := splitPrefix(name)
+	threshold := len(bareName) / 3
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	type scored struct {
+		name     string
+		distance int
+	}
+	var items []scored
+	seen := make(map[string]struct{})
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		if _, ok := seen[candidate]; ok {
+			continue
+		}
+		seen[candidate] = struct{}{}
+
+		candidatePrefix, bareCandidate := splitPrefix(candidate)
+		distance := utils.EditDistance(name, candidate)
+		// candidate in other file costs one more edit
+		d := utils.EditDistance(bareName, bareCandidate)
+		if candidatePrefix != prefix {
+			d++
+		}
+		if d < distance {
+			distance = d
+		}
+		if distance <= threshold {
+			items = append(items, scored{name: candidate, distance: distance})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].distance == items[j].distance {
+			return items[i].name < items[j].name
+		}
+		return items[i].distance < items[j].distance
+	})
+
+	var res []string
+	for i := 0; i < len(items) && i < maxSuggestions; i++ {
+		res = append(res, items[i].name)
+	}
+	return res
+}
+
+// splitPrefix splits include prefix from name like `base.User`
+func splitPrefix(name string) (string, string) {
+	if idx := strings.Index(name, "."); idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// typeCandidates returns base types and types defined in file and its includes. Types in includes
+// are prefixed by include name
+func typeCandidates(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document) []string {
+	res := []string{"bool", "byte", "i8", "i16", "i32", "i64", "double", "string", "binary", "uuid"}
+	codejump.ForEachScope(ctx, ss, file, ast, func(prefix string, _ uri.URI, doc *parser.Document) {
+		for _, node := range doc.Nodes {
+			if node.IsBadNode() {
+				continue
+			}
+			var name *parser.Identifier
+			switch n := node.(type) {
+			case *parser.Struct:
+				name = n.Identifier
+			case *parser.Union:
+				name = n.Name
+			case *parser.Exception:
+				name = n.Name
+			case *parser.Enum:
+				name = n.Name
+			case *parser.Typedef:
+				name = n.Alias
+			}
+			if name != nil && name.Name != nil {
+				res = append(res, prefix+name.Name.Text)
+			}
+		}
+	})
+
+	return res
+}
+
+// valueCandidates returns consts and enum values like `Status.OK` defined in file and its includes.
+// Values in includes are prefixed by include name
+func valueCandidates(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document) []string {
+	var res []string
+	codejump.ForEachScope(ctx, ss, file, ast, func(prefix string, _ uri.URI, doc *parser.Document) {
+		for _, cst := range doc.Consts {
+			if !cst.BadNode && cst.Name != nil && cst.Name.Name != nil {
+				res = append(res, prefix+cst.Name.Name.Text)
+			}
+		}
+		for _, enum := range doc.Enums {
+			if enum.BadNode || enum.Name == nil || enum.Name.Name == nil {
+				continue
+			}
+			for _, v := range enum.Values {
+				if !v.BadNode && v.Name != nil && v.Name.Name != nil {
+					res = append(res, prefix+enum.Name.Name.Text+"."+v.Name.Name.Text)
+				}
+			}
+		}
+	})
+
+	return res
+}
@@ -0,0 +1,61 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_suggest(t *testing.T) {
+	candidates := []string{"i32", "string", "UserInfo", "Status", "base.User", "base.Item"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "UserInf", want: []string{"UserInfo"}},
+		{name: "User", want: []string{"base.User"}},
+		{name: "bse.Item", want: []string{"base.Item"}},
+		{name: "strng", want: []string{"string"}},
+		{name: "Nothing", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, suggest(tt.name, candidates))
+		})
+	}
+}
+
+func Test_QuickFixes(t *testing.T) {
+	rng := protocol.Range{
+		Start: protocol.Position{Line: 1, Character: 4},
+		End:   protocol.Position{Line: 1, Character: 11},
+	}
+	d := withSuggestions(protocol.Diagnostic{
+		Range:   rng,
+		Source:  "thrift-ls",
+		Message: "field type doesn't exist",
+	}, nil, nil)
+	assert.Nil(t, d.Data)
+
+	d.Message = "field type doesn't exist, did you mean 'UserInfo'?"
+	d.Data = &SuggestionData{Range: rng, Suggestions: []string{"UserInfo", "base.UserInfo"}}
+
+	// data is a json object after sent to client and back
+	raw, err := json.Marshal(d)
+	assert.NoError(t, err)
+	var received protocol.Diagnostic
+	assert.NoError(t, json.Unmarshal(raw, &received))
+
+	actions := QuickFixes("file:///tmp/user.thrift", []protocol.Diagnostic{received, {Source: "other", Data: d.Data}})
+	assert.Len(t, actions, 2)
+	assert.Equal(t, "Change to 'UserInfo'", actions[0].Title)
+	assert.Equal(t, protocol.QuickFix, actions[0].Kind)
+	assert.True(t, actions[0].IsPreferred)
+	assert.False(t, actions[1].IsPreferred)
+	assert.Equal(t, map[uri.URI][]protocol.TextEdit{
+		"file:///tmp/user.thrift": {{Range: rng, NewText: "base.UserInfo"}},
+	}, actions[1].Edit.Changes)
+}
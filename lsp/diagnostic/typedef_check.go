@@ -0,0 +1,80 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"go.lsp.dev/uri"
+)
+
+// TypedefCheck checks typedef cycles and typedefs shadowing base types.
+// Undefined typedef target is reported by SemanticAnalysis
+type TypedefCheck struct {
+}
+
+func (c *TypedefCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	res := make(DiagnosticResult)
+	for _, file := range changeFiles {
+		items, err := c.diagnostic(ctx, ss, file)
+		if err != nil {
+			return nil, err
+		}
+		res[file] = items
+	}
+
+	return res, nil
+}
+
+func (c *TypedefCheck) Name() string {
+	return "TypedefCheck"
+}
+
+func (c *TypedefCheck) diagnostic(ctx context.Context, ss *cache.Snapshot, file uri.URI) ([]protocol.Diagnostic, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	var ret []protocol.Diagnostic
+	for _, td := range pf.AST().Typedefs {
+		if td.IsBadNode() || td.ChildrenBadNode() || td.Alias == nil || td.Alias.Name == nil {
+			continue
+		}
+
+		name := td.Alias.Name.Text
+		if codejump.IsBasicType(name) {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    lsputils.ASTNodeToRange(td.Alias),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("typedef %s shadows base type %s", name, name),
+			})
+		}
+
+		chain, err := codejump.ResolveTypedefChain(ctx, ss, file, pf.AST(), td)
+		// undefined types, like types of missing includes, are reported by SemanticAnalysis
+		if err != nil {
+			continue
+		}
+		// only typedefs in the cycle are reported, typedefs referring to a cycle are not
+		if chain.Cyclic && chain.Typedefs[len(chain.Typedefs)-1].Typedef == td {
+			ret = append(ret, protocol.Diagnostic{
+				Range:    lsputils.ASTNodeToRange(td.Alias),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "thrift-ls",
+				Message:  fmt.Sprintf("typedef cycle: %s", chain),
+			})
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,67 @@
+package diagnostic
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_TypedefCheck_Diagnostic(t *testing.T) {
+	base := `include "user.thrift"
+
+typedef user.Name Alias
+`
+	user := `include "base.thrift"
+include "missing.thrift"
+
+typedef missing.T Broken
+typedef base.Alias Name
+typedef Self Self
+typedef Name Ref
+typedef i64 string
+typedef list<Ref> Names
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/base.thrift",
+			Version: 0,
+			Content: []byte(base),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/user.thrift",
+			Version: 0,
+			Content: []byte(user),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	type diag struct {
+		line      uint32
+		character uint32
+		message   string
+	}
+	want := []diag{
+		{line: 4, character: 19, message: "typedef cycle: Name -> base.Alias -> Name"},
+		{line: 5, character: 13, message: "typedef cycle: Self -> Self"},
+		{line: 7, character: 12, message: "typedef string shadows base type string"},
+	}
+
+	c := &TypedefCheck{}
+	res, err := c.Diagnostic(context.TODO(), ss, []uri.URI{"file:///tmp/user.thrift"})
+	assert.NoError(t, err)
+
+	items := res["file:///tmp/user.thrift"]
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Range.Start.Line < items[j].Range.Start.Line
+	})
+	got := make([]diag, 0, len(items))
+	for _, item := range items {
+		got = append(got, diag{line: item.Range.Start.Line, character: item.Range.Start.Character, message: item.Message})
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,301 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"github.com/joyme123/thrift-ls/lsp/lsputils"
+	"github.com/joyme123/thrift-ls/parser"
+	"go.lsp.dev/uri"
+)
+
+// UnusedCheck reports includes never referenced in a file and definitions which can't be
+// reached from any service. Unused definition check is disabled by default
+type UnusedCheck struct {
+}
+
+func (c *UnusedCheck) Diagnostic(ctx context.Context, ss *cache.Snapshot, changeFiles []uri.URI) (DiagnosticResult, error) {
+	res := make(DiagnosticResult)
+	for _, file := range changeFiles {
+		items, err := c.diagnostic(ctx, ss, file)
+		if err != nil {
+			return nil, err
+		}
+		res[file] = items
+	}
+
+	return res, nil
+}
+
+func (c *UnusedCheck) Name() string {
+	return "UnusedCheck"
+}
+
+func (c *UnusedCheck) diagnostic(ctx context.Context, ss *cache.Snapshot, file uri.URI) ([]protocol.Diagnostic, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	opts := OptionsFromContext(ctx)
+
+	var ret []protocol.Diagnostic
+	if sev, ok := severity(opts.UnusedInclude, protocol.DiagnosticSeverityHint); ok {
+		ret = append(ret, c.checkIncludes(ctx, ss, file, pf.AST(), sev)...)
+	}
+	// unused definition check is opt-in
+	if opts.UnusedDefinition != "" {
+		if sev, ok := severity(opts.UnusedDefinition, protocol.DiagnosticSeverityHint); ok {
+			items, err := c.checkDefinitions(ctx, ss, file, pf.AST(), sev)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, items...)
+		}
+	}
+
+	return ret, nil
+}
+
+// checkIncludes reports includes whose names never prefix references. Values of local enums, like
+// `Status.OK`, don't use include with the same name as enum. Includes reported by IncludeCheck
+// are skipped
+func (c *UnusedCheck) checkIncludes(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document,
+	sev protocol.DiagnosticSeverity) []protocol.Diagnostic {
+	used := make(map[string]struct{})
+	for _, node := range ast.Nodes {
+		for _, ref := range references(node) {
+			if ref.value && lsputils.IsLocalEnumValue(ast, ref.name) {
+				continue
+			}
+			if include, _ := lsputils.ParseIdent(file, ast.Includes, ref.name); include != "" {
+				used[include] = struct{}{}
+			}
+		}
+	}
+
+	flagged := flaggedIncludes(ctx, ss, file, ast.Includes)
+	var ret []protocol.Diagnostic
+	for _, inc := range ast.Includes {
+		if inc.BadNode || inc.Path == nil || inc.Path.BadNode || inc.Path.Value == nil {
+			continue
+		}
+		if _, ok := flagged[inc]; ok {
+			continue
+		}
+		if _, ok := used[inc.Name()]; ok {
+			continue
+		}
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(inc),
+			Severity: sev,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("include %q is never used", inc.Path.Value.Text),
+			Tags:     []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary},
+		})
+	}
+
+	return ret
+}
+
+// checkDefinitions reports definitions of file which aren't reachable from services. Services in
+// file and files including it directly or indirectly are entry points. If there is no service,
+// nothing is reported because definitions may be used by files out of workspace
+func (c *UnusedCheck) checkDefinitions(ctx context.Context, ss *cache.Snapshot, file uri.URI,
+	ast *parser.Document, sev protocol.DiagnosticSeverity) ([]protocol.Diagnostic, error) {
+	type item struct {
+		file uri.URI
+		ast  *parser.Document
+		node parser.Node
+	}
+
+	var queue []item
+	for _, scopeFile := range ss.Graph().IncludedBy(file) {
+		pf, err := ss.Parse(ctx, scopeFile)
+		if err != nil || pf.AST() == nil {
+			continue
+		}
+		for _, svc := range pf.AST().Services {
+			if !svc.BadNode {
+				queue = append(queue, item{file: scopeFile, ast: pf.AST(), node: svc})
+			}
+		}
+	}
+	if len(queue) == 0 {
+		return nil, nil
+	}
+
+	used := make(map[parser.Node]struct{})
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, ref := range references(cur.node) {
+			dstFile, dstAst, node, err := resolveReference(ctx, ss, cur.file, cur.ast, ref)
+			// references which can't be resolved, like types of missing includes, are reported
+			// by other checks
+			if err != nil || node == nil {
+				continue
+			}
+			if _, ok := used[node]; ok {
+				continue
+			}
+			used[node] = struct{}{}
+			queue = append(queue, item{file: dstFile, ast: dstAst, node: node})
+		}
+	}
+
+	var ret []protocol.Diagnostic
+	for _, node := range ast.Nodes {
+		if node.IsBadNode() {
+			continue
+		}
+		// services are entry points
+		kind, name := codejump.DefinitionIdentifier(node)
+		if kind == "service" || name == nil || name.Name == nil {
+			continue
+		}
+		if _, ok := used[node]; ok {
+			continue
+		}
+		ret = append(ret, protocol.Diagnostic{
+			Range:    lsputils.ASTNodeToRange(name),
+			Severity: sev,
+			Source:   "thrift-ls",
+			Message:  fmt.Sprintf("%s %s is never used", kind, name.Name.Text),
+			Tags:     []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary},
+		})
+	}
+
+	return ret, nil
+}
+
+type reference struct {
+	name string
+	// value is true if name refers to const or enum value, otherwise it refers to type or service
+	value bool
+}
+
+// references returns type names, const values and extended service referred by definition node
+func references(node parser.Node) []reference {
+	var res []reference
+	var addType func(ft *parser.FieldType)
+	addType = func(ft *parser.FieldType) {
+		if ft == nil || ft.BadNode || ft.TypeName == nil {
+			return
+		}
+		if !codejump.IsBasicType(ft.TypeName.Name) {
+			res = append(res, reference{name: ft.TypeName.Name})
+		}
+		addType(ft.KeyType)
+		addType(ft.ValueType)
+	}
+	var addValue func(cv *parser.ConstValue)
+	addValue = func(cv *parser.ConstValue) {
+		if cv == nil || cv.BadNode {
+			return
+		}
+		switch cv.TypeName {
+		case "identifier":
+			if name, ok := cv.Value.(string); ok {
+				res = append(res, reference{name: name, value: true})
+			}
+		case "list", "map":
+			values, _ := cv.Value.([]*parser.ConstValue)
+			for _, v := range values {
+				addValue(v)
+			}
+		case "pair":
+			key, _ := cv.Key.(*parser.ConstValue)
+			value, _ := cv.Value.(*parser.ConstValue)
+			addValue(key)
+			addValue(value)
+		}
+	}
+	addFields := func(fields []*parser.Field) {
+		for _, field := range fields {
+			if field.BadNode {
+				continue
+			}
+			addType(field.FieldType)
+			addValue(field.ConstValue)
+		}
+	}
+
+	switch n := node.(type) {
+	case *parser.Struct:
+		addFields(n.Fields)
+	case *parser.Union:
+		addFields(n.Fields)
+	case *parser.Exception:
+		addFields(n.Fields)
+	case *parser.Typedef:
+		addType(n.T)
+	case *parser.Const:
+		addType(n.ConstType)
+		addValue(n.Value)
+	case *parser.Service:
+		if n.Extends != nil && n.Extends.Name != nil {
+			res = append(res, reference{name: n.Extends.Name.Text})
+		}
+		for _, fn := range n.Functions {
+			if fn.BadNode {
+				continue
+			}
+			addType(fn.FunctionType)
+			addFields(fn.Arguments)
+			if fn.Throws != nil {
+				addFields(fn.Throws.Fields)
+			}
+		}
+	}
+
+	return res
+}
+
+// resolveReference finds definition referred by ref in file
+func resolveReference(ctx context.Context, ss *cache.Snapshot, file uri.URI, ast *parser.Document,
+	ref reference) (uri.URI, *parser.Document, parser.Node, error) {
+	if !ref.value {
+		dstFile, dstAst, svc, node, err := codejump.ResolveService(ctx, ss, file, ast, ref.name)
+		if svc != nil {
+			return dstFile, dstAst, svc, err
+		}
+		return dstFile, dstAst, node, err
+	}
+
+	dstFile, enum, _, err := codejump.ResolveEnumValue(ctx, ss, file, ast, ref.name)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if enum != nil {
+		// enum has no reference, document isn't needed
+		return dstFile, nil, enum, nil
+	}
+
+	include, identifier := lsputils.ParseIdent(file, ast.Includes, ref.name)
+	dstFile = file
+	if include != "" {
+		path := lsputils.GetIncludePath(ast, include)
+		if path == "" {
+			return "", nil, nil, nil
+		}
+		dstFile = lsputils.IncludeURI(file, path)
+	}
+	pf, err := ss.Parse(ctx, dstFile)
+	if err != nil || pf.AST() == nil {
+		return "", nil, nil, err
+	}
+	if cst := codejump.GetConstNode(pf.AST(), identifier); cst != nil {
+		return dstFile, pf.AST(), cst, nil
+	}
+
+	return "", nil, nil, nil
+}
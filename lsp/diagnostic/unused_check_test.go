@@ -0,0 +1,232 @@
+package diagnostic
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func Test_UnusedCheck_Diagnostic(t *testing.T) {
+	base := `include "common.thrift"
+
+enum Status {
+  OK
+}
+
+struct Req {
+  1: Status status = Status.OK
+}
+
+struct Dead {
+  1: Alive alive
+}
+
+struct Alive {}
+
+typedef i64 ID
+
+const i32 Max = 10
+const list<i32> Limits = [Max]
+`
+	common := `struct Common {}`
+	api := `include "base.thrift"
+
+service Api {
+  base.Req Get(1: list<base.Alive> items, 2: i32 limit = base.Max)
+}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/base.thrift",
+			Version: 0,
+			Content: []byte(base),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/common.thrift",
+			Version: 0,
+			Content: []byte(common),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/api.thrift",
+			Version: 0,
+			Content: []byte(api),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+	// include graph is built when files are parsed
+	_, err := ss.Parse(context.TODO(), "file:///tmp/api.thrift")
+	assert.NoError(t, err)
+
+	type diag struct {
+		line      uint32
+		character uint32
+		message   string
+	}
+	tests := []struct {
+		name string
+		opts *Options
+		want []diag
+	}{
+		{
+			name: "default options",
+			opts: &Options{},
+			want: []diag{
+				{line: 0, character: 0, message: `include "common.thrift" is never used`},
+			},
+		},
+		{
+			name: "unused definitions",
+			opts: &Options{UnusedInclude: SeverityIgnore, UnusedDefinition: SeverityWarning},
+			want: []diag{
+				{line: 10, character: 7, message: "struct Dead is never used"},
+				{line: 16, character: 12, message: "typedef ID is never used"},
+				{line: 19, character: 16, message: "const Limits is never used"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &UnusedCheck{}
+			res, err := c.Diagnostic(WithOptions(context.TODO(), tt.opts), ss, []uri.URI{"file:///tmp/base.thrift"})
+			assert.NoError(t, err)
+
+			items := res["file:///tmp/base.thrift"]
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].Range.Start.Line < items[j].Range.Start.Line
+			})
+			got := make([]diag, 0, len(items))
+			for _, item := range items {
+				assert.Equal(t, []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary}, item.Tags)
+				got = append(got, diag{line: item.Range.Start.Line, character: item.Range.Start.Character, message: item.Message})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_UnusedCheck_IncludePrefix(t *testing.T) {
+	file := `include "Status.thrift"
+include "a.b.thrift"
+
+enum Status {
+  OK
+}
+
+struct Req {
+  1: Status status = Status.OK
+  2: a.b.Item item
+}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/Status.thrift",
+			Version: 0,
+			Content: []byte(`const i32 OK = 1`),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/a.b.thrift",
+			Version: 0,
+			Content: []byte(`struct Item {}`),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/req.thrift",
+			Version: 0,
+			Content: []byte(file),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	c := &UnusedCheck{}
+	res, err := c.Diagnostic(WithOptions(context.TODO(), &Options{}), ss, []uri.URI{"file:///tmp/req.thrift"})
+	assert.NoError(t, err)
+
+	items := res["file:///tmp/req.thrift"]
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, uint32(0), items[0].Range.Start.Line)
+		assert.Equal(t, `include "Status.thrift" is never used`, items[0].Message)
+	}
+}
+
+func Test_UnusedCheck_BrokenInclude(t *testing.T) {
+	file := `include "missing.thrift"
+include "common.thrift"
+
+struct Dead {}
+
+service Api {
+  missing.Resp Get()
+}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/common.thrift",
+			Version: 0,
+			Content: []byte(`struct Common {}`),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/api.thrift",
+			Version: 0,
+			Content: []byte(file),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	c := &UnusedCheck{}
+	res, err := c.Diagnostic(WithOptions(context.TODO(), &Options{UnusedDefinition: SeverityWarning}), ss, []uri.URI{"file:///tmp/api.thrift"})
+	assert.NoError(t, err)
+
+	items := res["file:///tmp/api.thrift"]
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Range.Start.Line < items[j].Range.Start.Line
+	})
+	messages := make([]string, 0, len(items))
+	for _, item := range items {
+		messages = append(messages, item.Message)
+	}
+	assert.Equal(t, []string{`include "common.thrift" is never used`, "struct Dead is never used"}, messages)
+}
+
+func Test_UnusedCheck_FlaggedInclude(t *testing.T) {
+	file := `include "req.thrift"
+include "common.thrift"
+include "common.thrift"
+include "not_exist.thrift"
+
+struct Req {}
+`
+	ss := buildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     "file:///tmp/common.thrift",
+			Version: 0,
+			Content: []byte(`struct Common {}`),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+		{
+			URI:     "file:///tmp/req.thrift",
+			Version: 0,
+			Content: []byte(file),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	c := &UnusedCheck{}
+	res, err := c.Diagnostic(WithOptions(context.TODO(), &Options{}), ss, []uri.URI{"file:///tmp/req.thrift"})
+	assert.NoError(t, err)
+
+	// self, duplicate and missing includes are reported by IncludeCheck
+	items := res["file:///tmp/req.thrift"]
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, uint32(1), items[0].Range.Start.Line)
+		assert.Equal(t, `include "common.thrift" is never used`, items[0].Message)
+	}
+}
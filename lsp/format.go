@@ -44,6 +44,7 @@ func (s *Server) formatting(ctx context.Context, params *protocol.DocumentFormat
 	if err != nil {
 		return nil, err
 	}
+	formatted = format.DetectTextStyle(bytes).Apply(formatted)
 
 	mp := mapper.NewMapper(fileURI, bytes)
 	endPos := mp.GetLSPEndPosition()
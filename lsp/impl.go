@@ -102,7 +102,7 @@ func (s *Server) completion(ctx context.Context, params *protocol.CompletionPara
 	}
 	defer release()
 
-	items, rng, err := completion.DefaultTokenCompletion.Completion(ctx, snapshot, &completion.CompletionRequest{
+	items, rng, err := completion.DefaultSemanticCompletion.Completion(ctx, snapshot, &completion.CompletionRequest{
 		TriggerKind: 0,
 		Pos: types.Position{
 			Line:      params.Position.Line,
@@ -136,12 +136,47 @@ func toLspCompletionList(items []*completion.CompletionItem, rng protocol.Range)
 			Preselect:        i == 0,
 			Deprecated:       items[i].Deprecated,
 			Documentation:    items[i].Documentation,
+
+			AdditionalTextEdits: items[i].AdditionalTextEdits,
+		}
+		if items[i].Data != nil {
+			item.Data = items[i].Data
 		}
 		list.Items = append(list.Items, item)
 	}
 	return list
 }
 
+func (s *Server) completionResolve(ctx context.Context, item *protocol.CompletionItem) (*protocol.CompletionItem, error) {
+	data := completion.ParseResolveData(item.Data)
+	if data == nil {
+		return item, nil
+	}
+
+	var snapshot *cache.Snapshot
+	if data.File != "" {
+		ss, release, _, err := s.getFileContext(ctx, data.File)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		snapshot = ss
+	}
+
+	doc, err := completion.Resolve(ctx, snapshot, data)
+	if err != nil {
+		return nil, err
+	}
+	if doc != "" {
+		item.Documentation = protocol.MarkupContent{
+			Kind:  protocol.Markdown,
+			Value: doc,
+		}
+	}
+
+	return item, nil
+}
+
 func (s *Server) getFileContext(ctx context.Context, uri uri.URI) (ss *cache.Snapshot, release func(), fh cache.FileHandle, err error) {
 	var view *cache.View
 	view, err = s.session.ViewOf(uri)
@@ -27,6 +27,10 @@ func (s *Server) initialize(ctx context.Context, params *protocol.InitializePara
 		folders = append(folders, uri.URI(ws.URI))
 	}
 
+	if err := s.options.Update(params.InitializationOptions); err != nil {
+		log.Errorf("invalid initialization options: %v", err)
+	}
+
 	log.Debugln("initialized folders: ", folders)
 	if len(folders) > 0 {
 		s.session.Initialize(func() {
@@ -74,6 +78,24 @@ func (s *Server) walkFoldersThriftFile(folder uri.URI) {
 	})
 }
 
+// thriftFileOperationFilters matches thrift files and folders which may contain thrift files
+var thriftFileOperationFilters = []protocol.FileOperationFilter{
+	{
+		Scheme: "file",
+		Pattern: protocol.FileOperationPattern{
+			Glob:    "**/*.thrift",
+			Matches: protocol.FileOperationPatternKindFile,
+		},
+	},
+	{
+		Scheme: "file",
+		Pattern: protocol.FileOperationPattern{
+			Glob:    "**",
+			Matches: protocol.FileOperationPatternKindFolder,
+		},
+	},
+}
+
 func initializeResult() *protocol.InitializeResult {
 	res := &protocol.InitializeResult{
 		Capabilities: protocol.ServerCapabilities{
@@ -165,8 +187,7 @@ func initializeResult() *protocol.InitializeResult {
 				Label: "thriftls",
 			},
 			CodeActionProvider: &protocol.CodeActionOptions{
-				// TODO(jpf): should support code actions
-				CodeActionKinds: []protocol.CodeActionKind{},
+				CodeActionKinds: []protocol.CodeActionKind{protocol.QuickFix},
 				ResolveProvider: false,
 			},
 			CodeLensProvider: &protocol.CodeLensOptions{
@@ -203,6 +224,7 @@ func initializeResult() *protocol.InitializeResult {
 			},
 			CallHierarchyProvider:      false,
 			LinkedEditingRangeProvider: false,
+			SelectionRangeProvider:     true,
 			SemanticTokensProvider: &protocol.SemanticTokensRegistrationOptions{
 				TextDocumentRegistrationOptions: protocol.TextDocumentRegistrationOptions{
 					DocumentSelector: []*protocol.DocumentFilter{
@@ -240,13 +262,13 @@ func initializeResult() *protocol.InitializeResult {
 						Filters: []protocol.FileOperationFilter{},
 					},
 					WillRename: &protocol.FileOperationRegistrationOptions{
-						Filters: []protocol.FileOperationFilter{},
+						Filters: thriftFileOperationFilters,
 					},
 					DidDelete: &protocol.FileOperationRegistrationOptions{
 						Filters: []protocol.FileOperationFilter{},
 					},
 					WillDelete: &protocol.FileOperationRegistrationOptions{
-						Filters: []protocol.FileOperationFilter{},
+						Filters: thriftFileOperationFilters,
 					},
 				},
 			},
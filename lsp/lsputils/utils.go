@@ -73,6 +73,16 @@ func IncludeURI(cur uri.URI, includePath string) uri.URI {
 	return uri.File(path)
 }
 
+// RelativeIncludePath returns path of target relative to directory of cur, it can be used in
+// include of cur
+func RelativeIncludePath(cur uri.URI, target uri.URI) string {
+	rel, err := filepath.Rel(filepath.Dir(cur.Filename()), target.Filename())
+	if err != nil {
+		return target.Filename()
+	}
+	return filepath.ToSlash(rel)
+}
+
 // ParseIdent parse an identifier. identifier format:
 //  1. identifier
 //  2. include.identifier
@@ -100,6 +110,26 @@ func ParseIdent(cur uri.URI, includes []*parser.Include, identifier string) (inc
 	return "", identifier
 }
 
+// IsLocalEnumValue returns true if name is `Enum.VALUE` and enum is declared in ast. Such names
+// aren't qualified by include name even if an include has the same name as enum
+func IsLocalEnumValue(ast *parser.Document, name string) bool {
+	idx := strings.LastIndex(name, ".")
+	if idx <= 0 {
+		return false
+	}
+	for _, enum := range ast.Enums {
+		if enum.BadNode || enum.Name == nil || enum.Name.Name == nil || enum.Name.Name.Text != name[:idx] {
+			continue
+		}
+		for _, v := range enum.Values {
+			if !v.BadNode && v.Name != nil && v.Name.Name != nil && v.Name.Name.Text == name[idx+1:] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IncludeNames returns include names from include ast nodes
 func IncludeNames(cur uri.URI, includes []*parser.Include) (includeNames []string) {
 	for _, inc := range includes {
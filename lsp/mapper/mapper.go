@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"unicode/utf8"
 
@@ -29,6 +30,11 @@ func NewMapper(fileURI uri.URI, content []byte) *Mapper {
 	}
 }
 
+// Content returns content which ast positions are relative to
+func (m *Mapper) Content() []byte {
+	return m.content
+}
+
 func (m *Mapper) initLineStart() {
 	m.lineInit.Do(func() {
 		nlines := bytes.Count(m.content, []byte("\n"))
@@ -65,21 +71,13 @@ func (m *Mapper) LSPPosToParserPosition(pos types.Position) (parser.Position, er
 		return parser.InvalidPosition, fmt.Errorf("invalid position line, request line: %d, total line: %d", line, len(m.lineStart))
 	}
 
+	lineStart, lineEnd := m.lineRange(int(pos.Line))
+
 	if !m.nonASCII {
 		col := int(pos.Character) + 1
-		offset := m.lineStart[pos.Line] + int(pos.Character)
-		if offset > len(m.content) {
-			return parser.InvalidPosition, fmt.Errorf("invalid position offset: %d, total content: %d, %s", offset, len(m.content), string(m.content))
-		}
-		var lineLength int
-		if int(pos.Line+1) >= len(m.lineStart) {
-			lineLength = len(m.content) - m.lineStart[pos.Line]
-		} else {
-			lineLength = m.lineStart[pos.Line+1] - m.lineStart[pos.Line]
-		}
-
-		if col > lineLength+1 { // if line length is 0, col is 1 means col is at end of line
-			return parser.InvalidPosition, fmt.Errorf("invalid position column: %d, line length: %d, %s", col, lineLength, string(m.content))
+		offset := lineStart + int(pos.Character)
+		if offset > lineEnd { // position at end of line is valid
+			return parser.InvalidPosition, fmt.Errorf("invalid position column: %d, line length: %d", col, lineEnd-lineStart)
 		}
 
 		return parser.Position{
@@ -89,53 +87,65 @@ func (m *Mapper) LSPPosToParserPosition(pos types.Position) (parser.Position, er
 		}, nil
 	}
 
-	lineStart := m.lineStart[pos.Line]
-	lineEnd := 0
-	if int(pos.Line) == len(m.lineStart)-1 {
-		lineEnd = len(m.content)
-	} else {
-		lineEnd = m.lineStart[pos.Line+1]
-	}
 	lineBytes := m.content[lineStart:lineEnd]
-
-	utf16Col := -1
-	bytesCol := -1
-	for len(lineBytes) > 0 {
-		if utf16Col >= int(pos.Character) {
-			break
-		}
-		utf16Col++
-		if lineBytes[0] < utf8.RuneSelf {
-			lineBytes = lineBytes[1:]
-			bytesCol++
-			continue
+	utf16Col := 0
+	col := 1
+	offset := lineStart
+	for utf16Col < int(pos.Character) {
+		if len(lineBytes) == 0 {
+			return parser.InvalidPosition, errors.New("invalid position character")
 		}
 
 		r, size := utf8.DecodeRune(lineBytes)
+		utf16Col++
 		if r >= 0x10000 {
 			utf16Col++
 		}
 		lineBytes = lineBytes[size:]
-		bytesCol += size
+		offset += size
+		col++
 	}
 
-	runeLen := utf8.RuneCount(m.content[lineStart : lineStart+bytesCol+1])
-	offset := lineStart + bytesCol
-	if offset >= len(m.content) {
-		return parser.InvalidPosition, errors.New("invalid position character")
+	return parser.Position{
+		Line:   line,
+		Col:    col,
+		Offset: offset,
+	}, nil
+}
+
+// OffsetToLSPPosition converts 0-based byte offset to utf16-based lsp position
+func (m *Mapper) OffsetToLSPPosition(offset int) types.Position {
+	m.initLineStart()
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(m.content) {
+		offset = len(m.content)
 	}
 
-	/*
-		if offset >= m.lineStart[pos.Line+1] {
-			return parser.InvalidPosition, errors.New("invalid position character")
+	line := sort.Search(len(m.lineStart), func(i int) bool {
+		return m.lineStart[i] > offset
+	}) - 1
+
+	return types.Position{
+		Line:      uint32(line),
+		Character: uint32(utf16Count(m.content[m.lineStart[line]:offset])),
+	}
+}
+
+// lineRange returns 0-based byte offsets of line content. line terminator "\n" or "\r\n" is excluded
+func (m *Mapper) lineRange(line int) (start int, end int) {
+	start = m.lineStart[line]
+	if line+1 < len(m.lineStart) {
+		end = m.lineStart[line+1] - 1
+		if end > start && m.content[end-1] == '\r' {
+			end--
 		}
-	*/
+	} else {
+		end = len(m.content)
+	}
 
-	return parser.Position{
-		Line:   line,
-		Col:    runeLen,
-		Offset: lineStart + bytesCol,
-	}, nil
+	return start, end
 }
 
 func utf16Count(contents []byte) int {
@@ -26,6 +26,9 @@ func TestMapper_LSPPosToParserPosition(t *testing.T) {
   1: required string name,
 }`
 
+	crlfContent := "struct demo {\r\n  1: required string name,\r\n}"
+	crlfRuneContent := "struct 😀😂 {\r\n  1: required string name,\r\n}"
+
 	tests := []struct {
 		name      string
 		fields    fields
@@ -165,6 +168,74 @@ func TestMapper_LSPPosToParserPosition(t *testing.T) {
 			want:      parser.InvalidPosition,
 			assertion: assert.Error,
 		},
+		{
+			name: "crlf",
+			fields: fields{
+				fileURI: "test/test.thrift",
+				content: []byte(crlfContent),
+			},
+			args: args{
+				pos: types.Position{
+					Line:      1,
+					Character: 5,
+				},
+			},
+			want: parser.Position{
+				Line:   2,
+				Col:    6,
+				Offset: 20,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "crlf end of line",
+			fields: fields{
+				fileURI: "test/test.thrift",
+				content: []byte(crlfContent),
+			},
+			args: args{
+				pos: types.Position{
+					Line:      1,
+					Character: 26,
+				},
+			},
+			want: parser.Position{
+				Line:   2,
+				Col:    27,
+				Offset: 41,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "crlf character exceeded",
+			fields: fields{
+				fileURI: "test/test.thrift",
+				content: []byte(crlfContent),
+			},
+			args: args{
+				pos: types.Position{
+					Line:      1,
+					Character: 27,
+				},
+			},
+			want:      parser.InvalidPosition,
+			assertion: assert.Error,
+		},
+		{
+			name: "crlf rune character exceeded",
+			fields: fields{
+				fileURI: "test/test.thrift",
+				content: []byte(crlfRuneContent),
+			},
+			args: args{
+				pos: types.Position{
+					Line:      0,
+					Character: 14,
+				},
+			},
+			want:      parser.InvalidPosition,
+			assertion: assert.Error,
+		},
 	}
 	for i := range tests {
 		tt := tests[i]
@@ -180,6 +251,44 @@ func TestMapper_LSPPosToParserPosition(t *testing.T) {
 	}
 }
 
+func TestMapper_OffsetToLSPPosition(t *testing.T) {
+	content := "struct 😀😂 {\r\n  1: required string name,\r\n}"
+
+	tests := []struct {
+		name   string
+		offset int
+		want   types.Position
+	}{
+		{
+			name:   "line start",
+			offset: 0,
+			want:   types.Position{Line: 0, Character: 0},
+		},
+		{
+			name:   "after emoji",
+			offset: 15,
+			want:   types.Position{Line: 0, Character: 11},
+		},
+		{
+			name:   "next line",
+			offset: 21,
+			want:   types.Position{Line: 1, Character: 2},
+		},
+		{
+			name:   "end of content",
+			offset: len(content),
+			want:   types.Position{Line: 2, Character: 1},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMapper("test/test.thrift", []byte(content))
+			assert.Equal(t, tt.want, m.OffsetToLSPPosition(tt.offset))
+		})
+	}
+}
+
 func Test_utf16Count(t *testing.T) {
 	type args struct {
 		contents []byte
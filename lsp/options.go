@@ -0,0 +1,56 @@
+package lsp
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/joyme123/thrift-ls/lsp/diagnostic"
+)
+
+// Options is configuration of language server. Client sends it by initializationOptions
+// or settings of workspace/didChangeConfiguration, e.g. {"diagnostic": {"negativeEnumValue": "ignore"}}.
+// settings can also be nested in "thriftls" section
+type Options struct {
+	Diagnostic diagnostic.Options `json:"diagnostic"`
+}
+
+type serverOptions struct {
+	mu   sync.RWMutex
+	opts Options
+}
+
+func (o *serverOptions) Get() Options {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.opts
+}
+
+// Update decodes options from client settings. Invalid settings are ignored
+func (o *serverOptions) Update(settings interface{}) error {
+	if settings == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	section := struct {
+		Thriftls *json.RawMessage `json:"thriftls"`
+	}{}
+	if err := json.Unmarshal(data, &section); err == nil && section.Thriftls != nil {
+		data = *section.Thriftls
+	}
+
+	opts := Options{}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	o.opts = opts
+	o.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_serverOptions_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings interface{}
+		want     Options
+	}{
+		{
+			name:     "nil settings",
+			settings: nil,
+			want:     Options{},
+		},
+		{
+			name: "top level settings",
+			settings: map[string]interface{}{
+				"diagnostic": map[string]interface{}{"negativeEnumValue": "ignore"},
+			},
+			want: func() Options {
+				opts := Options{}
+				opts.Diagnostic.NegativeEnumValue = "ignore"
+				return opts
+			}(),
+		},
+		{
+			name: "thriftls section",
+			settings: map[string]interface{}{
+				"thriftls": map[string]interface{}{
+					"diagnostic": map[string]interface{}{"negativeEnumValue": "error"},
+				},
+			},
+			want: func() Options {
+				opts := Options{}
+				opts.Diagnostic.NegativeEnumValue = "error"
+				return opts
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &serverOptions{}
+			assert.NoError(t, o.Update(tt.settings))
+			assert.Equal(t, tt.want, o.Get())
+		})
+	}
+}
@@ -2,9 +2,11 @@ package lsp
 
 import (
 	"context"
+	"strings"
 
 	"github.com/joyme123/protocol"
 	"github.com/joyme123/thrift-ls/lsp/codejump"
+	"go.lsp.dev/uri"
 )
 
 func (s *Server) prepareRename(ctx context.Context, params *protocol.PrepareRenameParams) (*protocol.Range, error) {
@@ -30,3 +32,40 @@ func (s *Server) rename(ctx context.Context, params *protocol.RenameParams) (*pr
 
 	return codejump.Rename(ctx, ss, params.TextDocument.URI, params.Position, params.NewName)
 }
+
+func (s *Server) willRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) (*protocol.WorkspaceEdit, error) {
+	if len(params.Files) == 0 {
+		return nil, nil
+	}
+	view, err := s.session.ViewOf(uri.URI(params.Files[0].OldURI))
+	if err != nil {
+		return nil, err
+	}
+	ss, release := view.Snapshot()
+	defer release()
+
+	return codejump.RenameFiles(ctx, ss, params.Files)
+}
+
+// willDeleteFiles doesn't change any file, files losing includes are shown as warning
+func (s *Server) willDeleteFiles(ctx context.Context, params *protocol.DeleteFilesParams) (*protocol.WorkspaceEdit, error) {
+	if len(params.Files) == 0 || s.client == nil {
+		return nil, nil
+	}
+	view, err := s.session.ViewOf(uri.URI(params.Files[0].URI))
+	if err != nil {
+		return nil, err
+	}
+	ss, release := view.Snapshot()
+	defer release()
+
+	warnings, err := codejump.DeleteFiles(ctx, ss, params.Files)
+	if err != nil || len(warnings) == 0 {
+		return nil, err
+	}
+
+	return nil, s.client.ShowMessage(ctx, &protocol.ShowMessageParams{
+		Type:    protocol.MessageTypeWarning,
+		Message: strings.Join(warnings, "\n"),
+	})
+}
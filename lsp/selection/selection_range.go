@@ -0,0 +1,176 @@
+package selection
+
+import (
+	"context"
+	"errors"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/mapper"
+	"github.com/joyme123/thrift-ls/lsp/types"
+	"github.com/joyme123/thrift-ls/parser"
+	"github.com/joyme123/thrift-ls/utils"
+	"go.lsp.dev/uri"
+)
+
+// SelectionRanges returns selection range of every position. Ranges grow from the innermost node at
+// position to the whole document along the node path, like identifier -> field type -> field ->
+// field list -> struct body -> struct with its doc comments
+func SelectionRanges(ctx context.Context, ss *cache.Snapshot, file uri.URI, positions []protocol.Position) ([]*types.SelectionRange, error) {
+	pf, err := ss.Parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pf.AST() == nil {
+		return nil, errors.New("parse ast failed")
+	}
+
+	res := make([]*types.SelectionRange, 0, len(positions))
+	for _, pos := range positions {
+		astPos, err := pf.Mapper().LSPPosToParserPosition(types.Position{Line: pos.Line, Character: pos.Character})
+		if err != nil {
+			return nil, err
+		}
+		nodePath := parser.SearchNodePathByPosition(pf.AST(), astPos)
+		res = append(res, selectionRange(pf.Mapper(), nodePath, astPos.Offset, pos))
+	}
+
+	return res, nil
+}
+
+// span is byte offset range of content, end is excluded. Node positions may cover leading
+// whitespaces and their line and column are not reliable, so only offsets are used
+type span struct {
+	start int
+	end   int
+}
+
+func (s span) contains(other span) bool {
+	return s.start <= other.start && other.end <= s.end
+}
+
+// trim removes leading and trailing whitespaces of span
+func (s span) trim(content []byte) span {
+	if s.end > len(content) {
+		s.end = len(content)
+	}
+	for s.start < s.end && isSpace(content[s.start]) {
+		s.start++
+	}
+	for s.end > s.start && isSpace(content[s.end-1]) {
+		s.end--
+	}
+	return s
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func (s span) toRange(mp *mapper.Mapper) protocol.Range {
+	start, end := mp.OffsetToLSPPosition(s.start), mp.OffsetToLSPPosition(s.end)
+	return protocol.Range{
+		Start: protocol.Position{Line: start.Line, Character: start.Character},
+		End:   protocol.Position{Line: end.Line, Character: end.Character},
+	}
+}
+
+// selectionRange builds selection range from node path. Spans not contained by outer span are
+// skipped, and spans equal to outer span are merged
+func selectionRange(mp *mapper.Mapper, nodePath []parser.Node, offset int, lspPos protocol.Position) *types.SelectionRange {
+	var spans []span
+	for _, node := range nodePath {
+		spans = append(spans, nodeSpan(node))
+		spans = append(spans, innerSpans(node, offset)...)
+	}
+
+	var res *types.SelectionRange
+	var outer *span
+	for i := range spans {
+		spans[i] = spans[i].trim(mp.Content())
+		if outer != nil && (!outer.contains(spans[i]) || *outer == spans[i]) {
+			continue
+		}
+		res = &types.SelectionRange{Range: spans[i].toRange(mp), Parent: res}
+		outer = &spans[i]
+	}
+
+	if res == nil {
+		// empty document
+		return &types.SelectionRange{Range: protocol.Range{Start: lspPos, End: lspPos}}
+	}
+	return res
+}
+
+// nodeSpan returns span of node including its children, like doc comments and annotations
+func nodeSpan(node parser.Node) span {
+	res := span{start: node.Pos().Offset, end: node.End().Offset}
+	for _, child := range node.Children() {
+		if utils.IsNil(child) || child.Pos().Line <= 0 {
+			continue
+		}
+		if child.Pos().Offset < res.start {
+			res.start = child.Pos().Offset
+		}
+		if child.End().Offset > res.end {
+			res.end = child.End().Offset
+		}
+	}
+	return res
+}
+
+// innerSpans returns body in brackets and list of fields, enum values or functions in it if
+// offset is in them
+func innerSpans(node parser.Node, offset int) []span {
+	var open, close parser.Node
+	var items []parser.Node
+	switch n := node.(type) {
+	case *parser.Struct:
+		open, close, items = n.LCurKeyword, n.RCurKeyword, fieldNodes(n.Fields)
+	case *parser.Union:
+		open, close, items = n.LCurKeyword, n.RCurKeyword, fieldNodes(n.Fields)
+	case *parser.Exception:
+		open, close, items = n.LCurKeyword, n.RCurKeyword, fieldNodes(n.Fields)
+	case *parser.Throws:
+		open, close, items = n.LParKeyword, n.RParKeyword, fieldNodes(n.Fields)
+	case *parser.Function:
+		open, close, items = n.LParKeyword, n.RParKeyword, fieldNodes(n.Arguments)
+	case *parser.Enum:
+		open, close = n.LCurKeyword, n.RCurKeyword
+		for _, v := range n.Values {
+			items = append(items, v)
+		}
+	case *parser.Service:
+		open, close = n.LCurKeyword, n.RCurKeyword
+		for _, fn := range n.Functions {
+			items = append(items, fn)
+		}
+	default:
+		return nil
+	}
+
+	var res []span
+	if !utils.IsNil(open) && !utils.IsNil(close) {
+		body := span{start: open.Pos().Offset, end: close.End().Offset}
+		if body.contains(span{start: offset, end: offset}) {
+			res = append(res, body)
+		}
+	}
+	if len(items) > 0 {
+		list := span{start: nodeSpan(items[0]).start, end: nodeSpan(items[len(items)-1]).end}
+		if list.contains(span{start: offset, end: offset}) {
+			res = append(res, list)
+		}
+	}
+
+	return res
+}
+
+func fieldNodes(fields []*parser.Field) []parser.Node {
+	res := make([]parser.Node, 0, len(fields))
+	for _, field := range fields {
+		res = append(res, field)
+	}
+	return res
+}
@@ -0,0 +1,98 @@
+package selection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/uri"
+)
+
+func TestSelectionRanges(t *testing.T) {
+	file := `// user info
+struct User {
+  1: required list<string> names,
+  2: optional i32 age
+}
+
+enum Status {
+  ACTIVE = 1,
+  DELETED = 2
+}
+`
+	fileURI := uri.File("/tmp/file.thrift")
+	ss := cache.BuildSnapshotForTest([]*cache.FileChange{
+		{
+			URI:     fileURI,
+			Content: []byte(file),
+			From:    cache.FileChangeTypeDidOpen,
+		},
+	})
+
+	rng := func(startLine, startChar, endLine, endChar uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: startChar},
+			End:   protocol.Position{Line: endLine, Character: endChar},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		position protocol.Position
+		want     []protocol.Range
+	}{
+		{
+			name:     "field name",
+			position: protocol.Position{Line: 2, Character: 29},
+			want: []protocol.Range{
+				rng(2, 27, 2, 32),
+				rng(2, 2, 2, 33),
+				rng(2, 2, 3, 21),
+				rng(1, 12, 4, 1),
+				rng(0, 0, 4, 1),
+				rng(0, 0, 9, 1),
+			},
+		},
+		{
+			name:     "list element type",
+			position: protocol.Position{Line: 2, Character: 20},
+			want: []protocol.Range{
+				rng(2, 19, 2, 25),
+				rng(2, 14, 2, 26),
+				rng(2, 2, 2, 33),
+				rng(2, 2, 3, 21),
+				rng(1, 12, 4, 1),
+				rng(0, 0, 4, 1),
+				rng(0, 0, 9, 1),
+			},
+		},
+		{
+			name:     "enum value",
+			position: protocol.Position{Line: 8, Character: 4},
+			want: []protocol.Range{
+				rng(8, 2, 8, 9),
+				rng(8, 2, 8, 13),
+				rng(7, 2, 8, 13),
+				rng(6, 12, 9, 1),
+				rng(6, 0, 9, 1),
+				rng(0, 0, 9, 1),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := SelectionRanges(context.TODO(), ss, fileURI, []protocol.Position{tt.position})
+			assert.NoError(t, err)
+			assert.Len(t, res, 1)
+
+			var got []protocol.Range
+			for sr := res[0]; sr != nil; sr = sr.Parent {
+				got = append(got, sr.Range)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/joyme123/thrift-ls/lsp/selection"
+	"github.com/joyme123/thrift-ls/lsp/types"
+)
+
+func (s *Server) selectionRange(ctx context.Context, params interface{}) ([]*types.SelectionRange, error) {
+	// params of non standard request are decoded as map
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	var selectionParams types.SelectionRangeParams
+	if err := json.Unmarshal(raw, &selectionParams); err != nil {
+		return nil, err
+	}
+
+	file := selectionParams.TextDocument.URI
+	view, err := s.session.ViewOf(file)
+	if err != nil {
+		return nil, err
+	}
+	ss, release := view.Snapshot()
+	defer release()
+
+	return selection.SelectionRanges(ctx, ss, file, selectionParams.Positions)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/joyme123/protocol"
 	"github.com/joyme123/thrift-ls/lsp/cache"
+	"github.com/joyme123/thrift-ls/lsp/types"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -13,6 +14,8 @@ type Server struct {
 	session *cache.Session
 
 	client protocol.Client
+
+	options serverOptions
 }
 
 func NewServer(c *cache.Cache, client protocol.Client) *Server {
@@ -54,7 +57,9 @@ func (s *Server) SetTrace(ctx context.Context, params *protocol.SetTraceParams)
 }
 
 func (s *Server) CodeAction(ctx context.Context, params *protocol.CodeActionParams) (result []protocol.CodeAction, err error) {
-	return nil, nil
+	log.Debugln("------------CodeAction called--------------")
+	defer log.Debugln("-----------CodeAction finish--------------")
+	return s.codeAction(ctx, params)
 }
 
 func (s *Server) CodeLens(ctx context.Context, params *protocol.CodeLensParams) (result []protocol.CodeLens, err error) {
@@ -76,11 +81,15 @@ func (s *Server) Completion(ctx context.Context, params *protocol.CompletionPara
 }
 
 func (s *Server) CompletionResolve(ctx context.Context, params *protocol.CompletionItem) (result *protocol.CompletionItem, err error) {
-	return nil, nil
+	log.Debugln("------------CompletionResolve called--------------")
+	defer log.Debugln("-----------CompletionResolve finish--------------")
+	return s.completionResolve(ctx, params)
 }
 
 func (s *Server) Declaration(ctx context.Context, params *protocol.DeclarationParams) (result []protocol.Location, err error) {
-	return nil, nil
+	log.Debugln("--------------------Declaration called----------------------")
+	defer log.Debugln("--------------------Declaration finish----------------------")
+	return s.declaration(ctx, params)
 }
 
 func (s *Server) Definition(ctx context.Context, params *protocol.DefinitionParams) (result []protocol.Location, err error) {
@@ -96,6 +105,9 @@ func (s *Server) DidChange(ctx context.Context, params *protocol.DidChangeTextDo
 }
 
 func (s *Server) DidChangeConfiguration(ctx context.Context, params *protocol.DidChangeConfigurationParams) (err error) {
+	if err := s.options.Update(params.Settings); err != nil {
+		log.Errorf("invalid configuration: %v", err)
+	}
 	return nil
 }
 
@@ -228,7 +240,9 @@ func (s *Server) DidCreateFiles(ctx context.Context, params *protocol.CreateFile
 }
 
 func (s *Server) WillRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) (result *protocol.WorkspaceEdit, err error) {
-	return nil, nil
+	log.Debugln("--------------------WillRenameFiles called----------------------")
+	defer log.Debugln("--------------------WillRenameFiles finish----------------------")
+	return s.willRenameFiles(ctx, params)
 }
 
 func (s *Server) DidRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) (err error) {
@@ -236,7 +250,9 @@ func (s *Server) DidRenameFiles(ctx context.Context, params *protocol.RenameFile
 }
 
 func (s *Server) WillDeleteFiles(ctx context.Context, params *protocol.DeleteFilesParams) (result *protocol.WorkspaceEdit, err error) {
-	return nil, nil
+	log.Debugln("--------------------WillDeleteFiles called----------------------")
+	defer log.Debugln("--------------------WillDeleteFiles finish----------------------")
+	return s.willDeleteFiles(ctx, params)
 }
 
 func (s *Server) DidDeleteFiles(ctx context.Context, params *protocol.DeleteFilesParams) (err error) {
@@ -285,5 +301,11 @@ func (s *Server) Moniker(ctx context.Context, params *protocol.MonikerParams) (r
 
 // Request handles all no standard request
 func (s *Server) Request(ctx context.Context, method string, params interface{}) (result interface{}, err error) {
+	switch method {
+	case types.MethodTextDocumentSelectionRange:
+		log.Debugln("--------------------SelectionRange called----------------------")
+		defer log.Debugln("--------------------SelectionRange finish----------------------")
+		return s.selectionRange(ctx, params)
+	}
 	return nil, nil
 }
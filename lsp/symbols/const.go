@@ -2,6 +2,7 @@ package symbols
 
 import (
 	"github.com/joyme123/protocol"
+	"github.com/joyme123/thrift-ls/format"
 	"github.com/joyme123/thrift-ls/lsp/lsputils"
 	"github.com/joyme123/thrift-ls/parser"
 )
@@ -13,6 +14,7 @@ func ConstSymbol(cst *parser.Const) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           cst.Name.Name.Text,
+		Detail:         detailWithDoc(format.MustFormatFieldType(cst.ConstType), cst.Doc()),
 		Kind:           protocol.SymbolKindConstant,
 		Range:          lsputils.ASTNodeToRange(cst.Name.Name),
 		SelectionRange: lsputils.ASTNodeToRange(cst.Name.Name),
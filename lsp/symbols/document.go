@@ -3,6 +3,7 @@ package symbols
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/joyme123/protocol"
 	"github.com/joyme123/thrift-ls/lsp/cache"
@@ -67,3 +68,16 @@ func DocumentSymbols(ctx context.Context, ss *cache.Snapshot, file uri.URI) []*p
 
 	return res
 }
+
+// detailWithDoc appends first line of doc to detail
+func detailWithDoc(detail string, doc string) string {
+	line, _, _ := strings.Cut(doc, "\n")
+	if line == "" {
+		return detail
+	}
+	if detail == "" {
+		return line
+	}
+
+	return detail + " - " + line
+}
@@ -15,7 +15,7 @@ func EnumSymbol(enum *parser.Enum) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           enum.Name.Name.Text,
-		Detail:         "Enum",
+		Detail:         detailWithDoc("Enum", enum.Doc()),
 		Kind:           protocol.SymbolKindEnum,
 		Range:          lsputils.ASTNodeToRange(enum.Name.Name),
 		SelectionRange: lsputils.ASTNodeToRange(enum.Name.Name),
@@ -40,7 +40,7 @@ func EnumValueSymbol(v *parser.EnumValue) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           v.Name.Name.Text,
-		Detail:         strconv.FormatInt(v.Value, 10),
+		Detail:         detailWithDoc(strconv.FormatInt(v.Value, 10), v.Doc()),
 		Kind:           protocol.SymbolKindNumber,
 		Range:          lsputils.ASTNodeToRange(v.Name.Name),
 		SelectionRange: lsputils.ASTNodeToRange(v.Name.Name),
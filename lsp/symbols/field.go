@@ -20,7 +20,7 @@ func FieldSymbol(field *parser.Field) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           field.Identifier.Name.Text,
-		Detail:         detail,
+		Detail:         detailWithDoc(detail, field.Doc()),
 		Kind:           protocol.SymbolKindField,
 		Range:          lsputils.ASTNodeToRange(field.Identifier.Name),
 		SelectionRange: lsputils.ASTNodeToRange(field.Identifier.Name),
@@ -13,6 +13,7 @@ func ServiceSymbol(svc *parser.Service) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           svc.Name.Name.Text,
+		Detail:         detailWithDoc("", svc.Doc()),
 		Kind:           protocol.SymbolKindInterface,
 		Range:          lsputils.ASTNodeToRange(svc.Name.Name),
 		SelectionRange: lsputils.ASTNodeToRange(svc.Name.Name),
@@ -36,6 +37,7 @@ func FunctionSymbol(fn *parser.Function) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           fn.Name.Name.Text,
+		Detail:         detailWithDoc("", fn.Doc()),
 		Kind:           protocol.SymbolKindFunction,
 		Range:          lsputils.ASTNodeToRange(fn.Name.Name),
 		SelectionRange: lsputils.ASTNodeToRange(fn.Name.Name),
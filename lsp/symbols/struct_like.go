@@ -13,7 +13,7 @@ func StructSymbol(st *parser.Struct) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           st.Identifier.Name.Text,
-		Detail:         "Struct",
+		Detail:         detailWithDoc("Struct", st.Doc()),
 		Kind:           protocol.SymbolKindStruct,
 		Range:          lsputils.ASTNodeToRange(st.Identifier.Name),
 		SelectionRange: lsputils.ASTNodeToRange(st.Identifier.Name),
@@ -38,7 +38,7 @@ func UnionSymbol(un *parser.Union) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           un.Name.Name.Text,
-		Detail:         "Union",
+		Detail:         detailWithDoc("Union", un.Doc()),
 		Kind:           protocol.SymbolKindStruct,
 		Range:          lsputils.ASTNodeToRange(un.Name.Name),
 		SelectionRange: lsputils.ASTNodeToRange(un.Name.Name),
@@ -63,7 +63,7 @@ func ExceptionSymbol(ex *parser.Exception) *protocol.DocumentSymbol {
 
 	res := &protocol.DocumentSymbol{
 		Name:           ex.Name.Name.Text,
-		Detail:         "Exception",
+		Detail:         detailWithDoc("Exception", ex.Doc()),
 		Kind:           protocol.SymbolKindStruct,
 		Range:          lsputils.ASTNodeToRange(ex.Name.Name),
 		SelectionRange: lsputils.ASTNodeToRange(ex.Name.Name),
@@ -10,7 +10,7 @@ import (
 func TypedefSymbol(td *parser.Typedef) *protocol.DocumentSymbol {
 	res := &protocol.DocumentSymbol{
 		Name:           td.Alias.Name.Text,
-		Detail:         format.MustFormatFieldType(td.T),
+		Detail:         detailWithDoc(format.MustFormatFieldType(td.T), td.Doc()),
 		Kind:           protocol.SymbolKindTypeParameter,
 		Range:          lsputils.ASTNodeToRange(td.Alias.Name),
 		SelectionRange: lsputils.ASTNodeToRange(td.Alias.Name),
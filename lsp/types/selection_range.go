@@ -0,0 +1,20 @@
+package types
+
+import "github.com/joyme123/protocol"
+
+// MethodTextDocumentSelectionRange is method of selection range request. It isn't supported by
+// protocol package, so it is handled as non standard request
+const MethodTextDocumentSelectionRange = "textDocument/selectionRange"
+
+// SelectionRangeParams is params of textDocument/selectionRange request
+type SelectionRangeParams struct {
+	TextDocument protocol.TextDocumentIdentifier `json:"textDocument"`
+	Positions    []protocol.Position             `json:"positions"`
+}
+
+// SelectionRange is a range around the cursor that user may be interested in selecting. Parent
+// contains Range
+type SelectionRange struct {
+	Range  protocol.Range  `json:"range"`
+	Parent *SelectionRange `json:"parent,omitempty"`
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/joyme123/thrift-ls/dump"
 	"github.com/joyme123/thrift-ls/format"
 	tlog "github.com/joyme123/thrift-ls/log"
 	"github.com/joyme123/thrift-ls/lsp"
@@ -40,7 +42,7 @@ func main_format(opt format.Options, file string) error {
 	}
 
 	thrift_file := filepath.Base(file)
-	ast, err := parser.Parse(thrift_file, content)
+	ast, err := parser.Parse(thrift_file, parser.StripBOM(content))
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -50,6 +52,8 @@ func main_format(opt format.Options, file string) error {
 		fmt.Println(err)
 		return err
 	}
+	// keep line ending and BOM of source file, avoid rewriting the whole file
+	formated = format.DetectTextStyle(content).Apply(formated)
 
 	if opt.Write {
 		var perms os.FileMode
@@ -80,9 +84,51 @@ func main_format(opt format.Options, file string) error {
 
 }
 
+// main_dump handles `thriftls dump --json [--resolve-includes] <file>`
+func main_dump(args []string) error {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "dump ast as json")
+	resolveIncludes := fs.Bool("resolve-includes", false, "dump all included files recursively")
+	fs.Parse(args)
+
+	if !*jsonOutput {
+		err := errors.New("must specified an output format, supported: --json")
+		fmt.Println(err)
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		err := errors.New("must specified a thrift file to dump")
+		fmt.Println(err)
+		return err
+	}
+
+	schema, err := dump.Dump(fs.Arg(0), *resolveIncludes)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	fmt.Println(string(data))
+
+	return nil
+}
+
 func main() {
 	rand.Seed(time.Now().UnixMilli())
 
+	if len(os.Args) > 1 && os.Args[1] == "dump" {
+		if err := main_dump(os.Args[2:]); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	formatter := false
 	formatFile := ""
 	flag.BoolVar(&formatter, "format", false, "use thrift-ls as a format tool")
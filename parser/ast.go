@@ -379,7 +379,7 @@ func (i *Include) SetComments(comments []*Comment, endLineComments []*Comment) {
 
 func (i *Include) Name() string {
 	_, file := path.Split(i.Path.Value.Text)
-	name := strings.TrimRight(file, path.Ext(file))
+	name := strings.TrimSuffix(file, path.Ext(file))
 	return name
 }
 
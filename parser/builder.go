@@ -0,0 +1,625 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Builders in this file construct ast nodes programmatically. Nodes built here
+// don't have source locations, all positions are zero. Keywords and separators
+// are filled so that they can be printed by the format package directly.
+
+func newBuilderKeyword(text string) Keyword {
+	return Keyword{
+		Literal: &KeywordLiteral{Text: text},
+	}
+}
+
+func newBuilderIdentifier(name string) *Identifier {
+	return NewIdentifier(NewIdentifierName(name, Location{}), nil, Location{})
+}
+
+func newBuilderLiteral(text string) *Literal {
+	return NewLiteral(nil, NewLiteralValue(text, Location{}), "\"", Location{})
+}
+
+func newBuilderListSeparator() *ListSeparatorKeyword {
+	return &ListSeparatorKeyword{Keyword: newBuilderKeyword(",")}
+}
+
+// newDocComment converts text to a doc comment like:
+//
+//	/**
+//	 * text
+//	 */
+func newDocComment(text string) *Comment {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) == 1 {
+		return NewComment("/** "+lines[0]+" */", CommentStyleMultiLine, Location{})
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString("/**\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			buf.WriteString(" *\n")
+		} else {
+			buf.WriteString(" * " + line + "\n")
+		}
+	}
+	buf.WriteString(" */")
+
+	return NewComment(buf.String(), CommentStyleMultiLine, Location{})
+}
+
+// definitionMeta holds doc comment and annotations shared by all builders
+type definitionMeta struct {
+	doc         string
+	annotations [][2]string
+}
+
+func (m *definitionMeta) comments() []*Comment {
+	if m.doc == "" {
+		return nil
+	}
+	return []*Comment{newDocComment(m.doc)}
+}
+
+func (m *definitionMeta) buildAnnotations() *Annotations {
+	if len(m.annotations) == 0 {
+		return nil
+	}
+
+	annos := make([]*Annotation, 0, len(m.annotations))
+	for i, kv := range m.annotations {
+		var sep *ListSeparatorKeyword
+		if i < len(m.annotations)-1 {
+			sep = newBuilderListSeparator()
+		}
+		annos = append(annos, NewAnnotation(&EqualKeyword{Keyword: newBuilderKeyword("=")}, sep,
+			newBuilderIdentifier(kv[0]), newBuilderLiteral(kv[1]), Location{}))
+	}
+
+	return NewAnnotations(&LParKeyword{Keyword: newBuilderKeyword("(")}, &RParKeyword{Keyword: newBuilderKeyword(")")}, annos, Location{})
+}
+
+// NewTypeFieldType returns field type for base type or user defined type.
+// name can be base type like i32, string, or identifier like User, base.User
+func NewTypeFieldType(name string) *FieldType {
+	return NewFieldType(nil, nil, nil, nil, &TypeName{Name: name}, nil, nil, Location{})
+}
+
+// NewListFieldType returns field type for list<elem>
+func NewListFieldType(elem *FieldType) *FieldType {
+	return NewFieldType(&LPointKeyword{Keyword: newBuilderKeyword("<")}, &RPointKeyword{Keyword: newBuilderKeyword(">")},
+		nil, nil, &TypeName{Name: "list"}, elem, nil, Location{})
+}
+
+// NewSetFieldType returns field type for set<elem>
+func NewSetFieldType(elem *FieldType) *FieldType {
+	return NewFieldType(&LPointKeyword{Keyword: newBuilderKeyword("<")}, &RPointKeyword{Keyword: newBuilderKeyword(">")},
+		nil, nil, &TypeName{Name: "set"}, elem, nil, Location{})
+}
+
+// NewMapFieldType returns field type for map<key,value>
+func NewMapFieldType(key, value *FieldType) *FieldType {
+	return NewFieldType(&LPointKeyword{Keyword: newBuilderKeyword("<")}, &RPointKeyword{Keyword: newBuilderKeyword(">")},
+		&CommaKeyword{Keyword: newBuilderKeyword(",")}, nil, &TypeName{Name: "map"}, key, value, Location{})
+}
+
+func NewIntConstValue(v int64) *ConstValue {
+	cv := NewConstValue("i64", v, Location{})
+	cv.ValueInText = strconv.FormatInt(v, 10)
+	return cv
+}
+
+func NewDoubleConstValue(v float64) *ConstValue {
+	cv := NewConstValue("double", v, Location{})
+	text := strconv.FormatFloat(v, 'f', -1, 64)
+	if !strings.ContainsAny(text, ".eE") {
+		// double constant must contain '.' or exponent, otherwise it will be parsed as int
+		text = text + ".0"
+	}
+	cv.ValueInText = text
+	return cv
+}
+
+// NewStringConstValue returns string const value. v is the text between quotes
+func NewStringConstValue(v string) *ConstValue {
+	return NewConstValue("string", newBuilderLiteral(v), Location{})
+}
+
+// NewIdentifierConstValue returns const value references other const or enum value, like Status.OK
+func NewIdentifierConstValue(v string) *ConstValue {
+	return NewConstValue("identifier", v, Location{})
+}
+
+func NewListConstValue(values ...*ConstValue) *ConstValue {
+	for i := range values {
+		if i < len(values)-1 {
+			values[i].ListSeparatorKeyword = newBuilderListSeparator()
+		} else {
+			values[i].ListSeparatorKeyword = nil
+		}
+	}
+
+	cv := NewConstValue("list", values, Location{})
+	cv.LBrkKeyword = &LBrkKeyword{Keyword: newBuilderKeyword("[")}
+	cv.RBrkKeyword = &RBrkKeyword{Keyword: newBuilderKeyword("]")}
+
+	return cv
+}
+
+type ConstMapBuilder struct {
+	pairs []*ConstValue
+}
+
+func NewConstMapBuilder() *ConstMapBuilder {
+	return &ConstMapBuilder{}
+}
+
+func (b *ConstMapBuilder) Put(key, value *ConstValue) *ConstMapBuilder {
+	pair := NewMapConstValue(key, value, Location{})
+	pair.ColonKeyword = &ColonKeyword{Keyword: newBuilderKeyword(":")}
+	b.pairs = append(b.pairs, pair)
+	return b
+}
+
+func (b *ConstMapBuilder) Build() *ConstValue {
+	for i := range b.pairs {
+		if i < len(b.pairs)-1 {
+			b.pairs[i].ListSeparatorKeyword = newBuilderListSeparator()
+		}
+	}
+
+	cv := NewConstValue("map", b.pairs, Location{})
+	cv.LCurKeyword = &LCurKeyword{Keyword: newBuilderKeyword("{")}
+	cv.RCurKeyword = &RCurKeyword{Keyword: newBuilderKeyword("}")}
+
+	return cv
+}
+
+type FieldBuilder struct {
+	index        int
+	required     string
+	fieldType    *FieldType
+	name         string
+	defaultValue *ConstValue
+
+	definitionMeta
+}
+
+func NewFieldBuilder(index int, fieldType *FieldType, name string) *FieldBuilder {
+	return &FieldBuilder{
+		index:     index,
+		fieldType: fieldType,
+		name:      name,
+	}
+}
+
+func (b *FieldBuilder) Required() *FieldBuilder {
+	b.required = "required"
+	return b
+}
+
+func (b *FieldBuilder) Optional() *FieldBuilder {
+	b.required = "optional"
+	return b
+}
+
+func (b *FieldBuilder) Default(value *ConstValue) *FieldBuilder {
+	b.defaultValue = value
+	return b
+}
+
+func (b *FieldBuilder) Doc(doc string) *FieldBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *FieldBuilder) Annotation(key, value string) *FieldBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *FieldBuilder) Build() *Field {
+	var required *RequiredKeyword
+	if b.required != "" {
+		required = &RequiredKeyword{Keyword: newBuilderKeyword(b.required)}
+	}
+
+	var equal *EqualKeyword
+	if b.defaultValue != nil {
+		equal = &EqualKeyword{Keyword: newBuilderKeyword("=")}
+	}
+
+	index := NewFieldIndex(&ColonKeyword{Keyword: newBuilderKeyword(":")}, b.index, nil, Location{})
+
+	return NewField(equal, nil, b.comments(), nil, b.buildAnnotations(), index, required,
+		b.fieldType, newBuilderIdentifier(b.name), b.defaultValue, Location{})
+}
+
+type StructBuilder struct {
+	name   string
+	fields []*Field
+
+	definitionMeta
+}
+
+func NewStructBuilder(name string) *StructBuilder {
+	return &StructBuilder{name: name}
+}
+
+func (b *StructBuilder) AddField(field *Field) *StructBuilder {
+	b.fields = append(b.fields, field)
+	return b
+}
+
+func (b *StructBuilder) Doc(doc string) *StructBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *StructBuilder) Annotation(key, value string) *StructBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *StructBuilder) Build() *Struct {
+	st := NewStruct(&StructKeyword{Keyword: newBuilderKeyword("struct")},
+		&LCurKeyword{Keyword: newBuilderKeyword("{")}, &RCurKeyword{Keyword: newBuilderKeyword("}")},
+		newBuilderIdentifier(b.name), b.fields, Location{})
+	st.SetComments(b.comments(), nil)
+	st.SetAnnotations(b.buildAnnotations())
+
+	return st
+}
+
+type UnionBuilder struct {
+	name   string
+	fields []*Field
+
+	definitionMeta
+}
+
+func NewUnionBuilder(name string) *UnionBuilder {
+	return &UnionBuilder{name: name}
+}
+
+func (b *UnionBuilder) AddField(field *Field) *UnionBuilder {
+	b.fields = append(b.fields, field)
+	return b
+}
+
+func (b *UnionBuilder) Doc(doc string) *UnionBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *UnionBuilder) Annotation(key, value string) *UnionBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *UnionBuilder) Build() *Union {
+	u := NewUnion(&UnionKeyword{Keyword: newBuilderKeyword("union")},
+		&LCurKeyword{Keyword: newBuilderKeyword("{")}, &RCurKeyword{Keyword: newBuilderKeyword("}")},
+		newBuilderIdentifier(b.name), b.fields, Location{})
+	u.SetComments(b.comments(), nil)
+	u.SetAnnotations(b.buildAnnotations())
+
+	return u
+}
+
+type ExceptionBuilder struct {
+	name   string
+	fields []*Field
+
+	definitionMeta
+}
+
+func NewExceptionBuilder(name string) *ExceptionBuilder {
+	return &ExceptionBuilder{name: name}
+}
+
+func (b *ExceptionBuilder) AddField(field *Field) *ExceptionBuilder {
+	b.fields = append(b.fields, field)
+	return b
+}
+
+func (b *ExceptionBuilder) Doc(doc string) *ExceptionBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *ExceptionBuilder) Annotation(key, value string) *ExceptionBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *ExceptionBuilder) Build() *Exception {
+	e := NewException(&ExceptionKeyword{Keyword: newBuilderKeyword("exception")},
+		&LCurKeyword{Keyword: newBuilderKeyword("{")}, &RCurKeyword{Keyword: newBuilderKeyword("}")},
+		newBuilderIdentifier(b.name), b.fields, Location{})
+	e.SetComments(b.comments(), nil)
+	e.SetAnnotations(b.buildAnnotations())
+
+	return e
+}
+
+type EnumBuilder struct {
+	name   string
+	values []*EnumValue
+
+	definitionMeta
+}
+
+func NewEnumBuilder(name string) *EnumBuilder {
+	return &EnumBuilder{name: name}
+}
+
+// AddValue adds enum value without explicit value. It's value is previous value + 1
+func (b *EnumBuilder) AddValue(name string) *EnumBuilder {
+	b.values = append(b.values, NewEnumValue(nil, nil, newBuilderIdentifier(name), nil, -1, nil, Location{}))
+	return b
+}
+
+// AddValueWithNumber adds enum value with explicit value, like `A = 1`
+func (b *EnumBuilder) AddValueWithNumber(name string, value int64) *EnumBuilder {
+	b.values = append(b.values, NewEnumValue(nil, &EqualKeyword{Keyword: newBuilderKeyword("=")},
+		newBuilderIdentifier(name), NewIntConstValue(value), value, nil, Location{}))
+	return b
+}
+
+func (b *EnumBuilder) Doc(doc string) *EnumBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *EnumBuilder) Annotation(key, value string) *EnumBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *EnumBuilder) Build() *Enum {
+	AssignEnumValues(b.values)
+
+	e := NewEnum(&EnumKeyword{Keyword: newBuilderKeyword("enum")},
+		&LCurKeyword{Keyword: newBuilderKeyword("{")}, &RCurKeyword{Keyword: newBuilderKeyword("}")},
+		newBuilderIdentifier(b.name), b.values, Location{})
+	e.SetComments(b.comments(), nil)
+	e.SetAnnotations(b.buildAnnotations())
+
+	return e
+}
+
+type FunctionBuilder struct {
+	name       string
+	returnType *FieldType
+	oneway     bool
+	arguments  []*Field
+	throws     []*Field
+
+	definitionMeta
+}
+
+// NewFunctionBuilder returns function builder. If returnType is nil, function returns void
+func NewFunctionBuilder(name string, returnType *FieldType) *FunctionBuilder {
+	return &FunctionBuilder{
+		name:       name,
+		returnType: returnType,
+	}
+}
+
+func (b *FunctionBuilder) Oneway() *FunctionBuilder {
+	b.oneway = true
+	return b
+}
+
+func (b *FunctionBuilder) AddArgument(arg *Field) *FunctionBuilder {
+	b.arguments = append(b.arguments, arg)
+	return b
+}
+
+func (b *FunctionBuilder) AddThrows(field *Field) *FunctionBuilder {
+	b.throws = append(b.throws, field)
+	return b
+}
+
+func (b *FunctionBuilder) Doc(doc string) *FunctionBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *FunctionBuilder) Annotation(key, value string) *FunctionBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *FunctionBuilder) Build() *Function {
+	var oneway *OnewayKeyword
+	if b.oneway {
+		oneway = &OnewayKeyword{Keyword: newBuilderKeyword("oneway")}
+	}
+
+	var void *VoidKeyword
+	if b.returnType == nil {
+		void = &VoidKeyword{Keyword: newBuilderKeyword("void")}
+	}
+
+	var throws *Throws
+	if len(b.throws) > 0 {
+		throws = NewThrows(&ThrowsKeyword{Keyword: newBuilderKeyword("throws")},
+			&LParKeyword{Keyword: newBuilderKeyword("(")}, &RParKeyword{Keyword: newBuilderKeyword(")")},
+			withListSeparators(b.throws), Location{})
+	}
+
+	return NewFunction(&LParKeyword{Keyword: newBuilderKeyword("(")}, &RParKeyword{Keyword: newBuilderKeyword(")")},
+		nil, newBuilderIdentifier(b.name), oneway, void, b.returnType, withListSeparators(b.arguments), throws,
+		b.comments(), nil, b.buildAnnotations(), Location{})
+}
+
+// withListSeparators sets ',' between fields printed in one line, like function arguments
+func withListSeparators(fields []*Field) []*Field {
+	for i := range fields {
+		if i < len(fields)-1 {
+			fields[i].ListSeparatorKeyword = newBuilderListSeparator()
+		} else {
+			fields[i].ListSeparatorKeyword = nil
+		}
+	}
+	return fields
+}
+
+type ServiceBuilder struct {
+	name      string
+	extends   string
+	functions []*Function
+
+	definitionMeta
+}
+
+func NewServiceBuilder(name string) *ServiceBuilder {
+	return &ServiceBuilder{name: name}
+}
+
+func (b *ServiceBuilder) Extends(service string) *ServiceBuilder {
+	b.extends = service
+	return b
+}
+
+func (b *ServiceBuilder) AddFunction(fn *Function) *ServiceBuilder {
+	b.functions = append(b.functions, fn)
+	return b
+}
+
+func (b *ServiceBuilder) Doc(doc string) *ServiceBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *ServiceBuilder) Annotation(key, value string) *ServiceBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *ServiceBuilder) Build() *Service {
+	var extendsKeyword *ExtendsKeyword
+	var extends *Identifier
+	if b.extends != "" {
+		extendsKeyword = &ExtendsKeyword{Keyword: newBuilderKeyword("extends")}
+		extends = newBuilderIdentifier(b.extends)
+	}
+
+	svc := NewService(&ServiceKeyword{Keyword: newBuilderKeyword("service")}, extendsKeyword,
+		&LCurKeyword{Keyword: newBuilderKeyword("{")}, &RCurKeyword{Keyword: newBuilderKeyword("}")},
+		newBuilderIdentifier(b.name), extends, b.functions, Location{})
+	svc.SetComments(b.comments(), nil)
+	svc.SetAnnotations(b.buildAnnotations())
+
+	return svc
+}
+
+type ConstBuilder struct {
+	constType *FieldType
+	name      string
+	value     *ConstValue
+
+	definitionMeta
+}
+
+func NewConstBuilder(constType *FieldType, name string, value *ConstValue) *ConstBuilder {
+	return &ConstBuilder{
+		constType: constType,
+		name:      name,
+		value:     value,
+	}
+}
+
+func (b *ConstBuilder) Doc(doc string) *ConstBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *ConstBuilder) Build() *Const {
+	cst := NewConst(&ConstKeyword{Keyword: newBuilderKeyword("const")}, &EqualKeyword{Keyword: newBuilderKeyword("=")},
+		nil, newBuilderIdentifier(b.name), b.constType, b.value, Location{})
+	cst.SetComments(b.comments(), nil)
+
+	return cst
+}
+
+type TypedefBuilder struct {
+	t     *FieldType
+	alias string
+
+	definitionMeta
+}
+
+func NewTypedefBuilder(t *FieldType, alias string) *TypedefBuilder {
+	return &TypedefBuilder{
+		t:     t,
+		alias: alias,
+	}
+}
+
+func (b *TypedefBuilder) Doc(doc string) *TypedefBuilder {
+	b.doc = doc
+	return b
+}
+
+func (b *TypedefBuilder) Annotation(key, value string) *TypedefBuilder {
+	b.annotations = append(b.annotations, [2]string{key, value})
+	return b
+}
+
+func (b *TypedefBuilder) Build() *Typedef {
+	td := NewTypedef(&TypedefKeyword{Keyword: newBuilderKeyword("typedef")}, b.t, newBuilderIdentifier(b.alias), Location{})
+	td.SetComments(b.comments(), nil)
+	td.SetAnnotations(b.buildAnnotations())
+
+	return td
+}
+
+type DocumentBuilder struct {
+	filename string
+	headers  []Header
+	defs     []Definition
+}
+
+func NewDocumentBuilder(filename string) *DocumentBuilder {
+	return &DocumentBuilder{filename: filename}
+}
+
+func (b *DocumentBuilder) AddInclude(path string) *DocumentBuilder {
+	b.headers = append(b.headers, NewInclude(&IncludeKeyword{Keyword: newBuilderKeyword("include")}, newBuilderLiteral(path), Location{}))
+	return b
+}
+
+func (b *DocumentBuilder) AddCPPInclude(path string) *DocumentBuilder {
+	b.headers = append(b.headers, NewCPPInclude(&CPPIncludeKeyword{Keyword: newBuilderKeyword("cpp_include")}, newBuilderLiteral(path), Location{}))
+	return b
+}
+
+// AddNamespace adds namespace header, like `namespace go user`
+func (b *DocumentBuilder) AddNamespace(scope string, name string) *DocumentBuilder {
+	language := &NamespaceScope{Identifier: *newBuilderIdentifier(scope)}
+	b.headers = append(b.headers, NewNamespace(&NamespaceKeyword{Keyword: newBuilderKeyword("namespace")}, language,
+		newBuilderIdentifier(name), nil, Location{}))
+	return b
+}
+
+// AddDefinition adds struct, union, exception, enum, service, const or typedef to document
+func (b *DocumentBuilder) AddDefinition(def Definition) *DocumentBuilder {
+	b.defs = append(b.defs, def)
+	return b
+}
+
+// Build returns document. headers are placed before definitions
+func (b *DocumentBuilder) Build() *Document {
+	doc := NewDocument(b.headers, b.defs, nil, Location{})
+	doc.Filename = b.filename
+
+	return doc
+}
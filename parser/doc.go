@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Doc returns documentation of struct. It is built from leading comments and end line comments
+func (s *Struct) Doc() string {
+	line := 0
+	if s.StructKeyword != nil {
+		line = s.StructKeyword.Pos().Line
+	}
+	return docFromComments(s.Comments, s.EndLineComments, line)
+}
+
+func (u *Union) Doc() string {
+	line := 0
+	if u.UnionKeyword != nil {
+		line = u.UnionKeyword.Pos().Line
+	}
+	return docFromComments(u.Comments, u.EndLineComments, line)
+}
+
+func (e *Exception) Doc() string {
+	line := 0
+	if e.ExceptionKeyword != nil {
+		line = e.ExceptionKeyword.Pos().Line
+	}
+	return docFromComments(e.Comments, e.EndLineComments, line)
+}
+
+func (e *Enum) Doc() string {
+	line := 0
+	if e.EnumKeyword != nil {
+		line = e.EnumKeyword.Pos().Line
+	}
+	return docFromComments(e.Comments, e.EndLineComments, line)
+}
+
+func (e *EnumValue) Doc() string {
+	line := 0
+	if e.Name != nil {
+		line = e.Name.Pos().Line
+	}
+	return docFromComments(e.Comments, e.EndLineComments, line)
+}
+
+func (s *Service) Doc() string {
+	line := 0
+	if s.ServiceKeyword != nil {
+		line = s.ServiceKeyword.Pos().Line
+	}
+	return docFromComments(s.Comments, s.EndLineComments, line)
+}
+
+func (f *Function) Doc() string {
+	line := 0
+	if f.Oneway != nil {
+		line = f.Oneway.Pos().Line
+	} else if f.Void != nil {
+		line = f.Void.Pos().Line
+	} else if f.FunctionType != nil {
+		line = f.FunctionType.Pos().Line
+	}
+	return docFromComments(f.Comments, f.EndLineComments, line)
+}
+
+func (f *Field) Doc() string {
+	line := 0
+	if f.Index != nil {
+		line = f.Index.Pos().Line
+	}
+	return docFromComments(f.Comments, f.EndLineComments, line)
+}
+
+func (c *Const) Doc() string {
+	line := 0
+	if c.ConstKeyword != nil {
+		line = c.ConstKeyword.Pos().Line
+	}
+	return docFromComments(c.Comments, c.EndLineComments, line)
+}
+
+func (t *Typedef) Doc() string {
+	line := 0
+	if t.TypedefKeyword != nil {
+		line = t.TypedefKeyword.Pos().Line
+	}
+	return docFromComments(t.Comments, t.EndLineComments, line)
+}
+
+// docFromComments converts comments to clean text. Only the last group of leading comments is
+// used, comments separated by empty line from node (like license header) are not documentation.
+// nodeLine is line of first token of node. If nodeLine is 0, it is ignored.
+func docFromComments(comments []*Comment, endLineComments []*Comment, nodeLine int) string {
+	start := len(comments)
+	nextLine := nodeLine
+	for i := len(comments) - 1; i >= 0; i-- {
+		if nextLine > 0 && comments[i].End().Line > 0 && nextLine-comments[i].End().Line > 1 {
+			break
+		}
+		start = i
+		nextLine = comments[i].Pos().Line
+	}
+
+	lines := make([]string, 0)
+	for _, c := range comments[start:] {
+		lines = append(lines, commentLines(c)...)
+	}
+	if len(lines) > 0 && len(endLineComments) > 0 {
+		lines = append(lines, "")
+	}
+	for _, c := range endLineComments {
+		lines = append(lines, commentLines(c)...)
+	}
+
+	// remove empty lines at start and end
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// commentLines strips comment delimiters and '*' gutters
+func commentLines(c *Comment) []string {
+	text := strings.TrimSpace(c.Text)
+	switch c.Style {
+	case CommentStyleMultiLine:
+		text = strings.TrimPrefix(text, "/*")
+		text = strings.TrimSuffix(text, "*/")
+		text = strings.TrimLeft(text, "*") // /** xxx */
+		res := make([]string, 0)
+		for _, line := range strings.Split(text, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "*") {
+				line = strings.TrimPrefix(line, "*")
+				line = strings.TrimPrefix(line, " ")
+			}
+			res = append(res, strings.TrimRight(line, " \t\r"))
+		}
+		return res
+	case CommentStyleSingleLine:
+		text = strings.TrimLeft(text, "/") // // xxx, /// xxx
+	case CommentStyleShell:
+		text = strings.TrimPrefix(text, "#")
+	}
+
+	return []string{strings.TrimSpace(text)}
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoc(t *testing.T) {
+	demoContent := `/*
+ * license header
+ */
+
+/**
+ * User is a user.
+ *
+ * It has a name.
+ */
+struct User {
+    /// id of user
+    /// must be positive
+    1: i64 id // trailing doc
+    # shell doc
+    2: string name
+    3: string nodoc
+}
+
+// Status of user
+enum Status {
+    /** ok */
+    OK
+    Failed // failed
+}
+
+/* plain */
+service UserService {
+    // get user
+    User GetUser(1: i64 id)
+}
+
+// license
+
+typedef i64 UserID
+`
+
+	ast, err := Parse("test.thrift", []byte(demoContent))
+	assert.NoError(t, err)
+	doc := ast.(*Document)
+
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "struct with license header",
+			doc:  doc.Structs[0].Doc(),
+			want: "User is a user.\n\nIt has a name.",
+		},
+		{
+			name: "field with multiple line comments and end line comment",
+			doc:  doc.Structs[0].Fields[0].Doc(),
+			want: "id of user\nmust be positive\n\ntrailing doc",
+		},
+		{
+			name: "field with shell comment",
+			doc:  doc.Structs[0].Fields[1].Doc(),
+			want: "shell doc",
+		},
+		{
+			name: "field without comment",
+			doc:  doc.Structs[0].Fields[2].Doc(),
+			want: "",
+		},
+		{
+			name: "enum",
+			doc:  doc.Enums[0].Doc(),
+			want: "Status of user",
+		},
+		{
+			name: "enum value with one line doc",
+			doc:  doc.Enums[0].Values[0].Doc(),
+			want: "ok",
+		},
+		{
+			name: "enum value with end line comment",
+			doc:  doc.Enums[0].Values[1].Doc(),
+			want: "failed",
+		},
+		{
+			name: "service",
+			doc:  doc.Services[0].Doc(),
+			want: "plain",
+		},
+		{
+			name: "function",
+			doc:  doc.Services[0].Functions[0].Doc(),
+			want: "get user",
+		},
+		{
+			name: "comment separated by empty line",
+			doc:  doc.Typedefs[0].Doc(),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.doc)
+		})
+	}
+}
@@ -18,7 +18,7 @@ func (p *PEGParser) Parse(filename string, content []byte) (*Document, []error)
 	}
 	p.parsed[filename] = struct{}{}
 
-	doc, err := Parse(filename, content)
+	doc, err := Parse(filename, StripBOM(content))
 	if err != nil {
 		var errors []error
 		errList, ok := err.(ErrorLister)
@@ -34,3 +34,20 @@ include "../base.thrift
 	assert.Equal(t, "../user.thrift", includes[0].Path.Value.Text)
 	assert.Equal(t, true, includes[1].Path.BadNode)
 }
+
+func Test_IncludeName(t *testing.T) {
+	demoContent := `include "shift.thrift"
+include "../base.thrift"
+include "a.b.thrift"
+`
+	ast, err := parser.Parse("test.thrift", []byte(demoContent))
+	assert.NoError(t, err)
+	assert.NotNil(t, ast)
+
+	includes := ast.(*parser.Document).Includes
+	assert.Len(t, includes, 3)
+	// extension is trimmed as suffix, not as set of characters
+	assert.Equal(t, "shift", includes[0].Name())
+	assert.Equal(t, "base", includes[1].Name())
+	assert.Equal(t, "a.b", includes[2].Name())
+}
@@ -130,17 +130,10 @@ func toEnumValueSlice(v any) []*EnumValue {
 	values := v.([]any)
 
 	ret := make([]*EnumValue, 0, len(values))
-	value := int64(0)
 	for i := range values {
-		enumV := values[i].(*EnumValue)
-		if enumV.ValueNode == nil {
-			enumV.Value = value
-		} else {
-			value = enumV.Value
-		}
-		value++
-		ret = append(ret, enumV)
+		ret = append(ret, values[i].(*EnumValue))
 	}
+	AssignEnumValues(ret)
 	return ret
 }
 
@@ -1,5 +1,29 @@
 package parser
 
+import "bytes"
+
 func StringPointer(s string) *string {
 	return &s
 }
+
+// UTF8BOM is byte order mark of UTF-8 file
+var UTF8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// StripBOM removes leading UTF-8 BOM of content. Positions of ast are relative to content without BOM
+func StripBOM(content []byte) []byte {
+	return bytes.TrimPrefix(content, UTF8BOM)
+}
+
+// AssignEnumValues sets Value of enum values without explicit value, the same as Apache compiler does:
+// the first value is 0 and an implicit value is previous value + 1
+func AssignEnumValues(values []*EnumValue) {
+	value := int64(0)
+	for _, v := range values {
+		if v.ValueNode == nil {
+			v.Value = value
+		} else {
+			value = v.Value
+		}
+		value++
+	}
+}